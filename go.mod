@@ -1,3 +1,8 @@
 module github.com/mafalt/go-logging
 
 go 1.13
+
+require (
+	golang.org/x/sys v0.0.0-20210423082822-04245dca01da
+	gopkg.in/yaml.v2 v2.4.0
+)
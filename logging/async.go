@@ -0,0 +1,189 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultBufferSize = 100
+
+// Overflow policies of async logger applied when its queue is full
+const (
+	// OverflowBlock blocks the caller until there is space in the queue
+	OverflowBlock = "block"
+	// OverflowDropNewest drops the message being written
+	OverflowDropNewest = "drop-newest"
+	// OverflowDropOldest drops the oldest queued message to make room for the new one
+	OverflowDropOldest = "drop-oldest"
+)
+
+func validOverflowPolicy(policy string) bool {
+	switch policy {
+	case "", OverflowBlock, OverflowDropNewest, OverflowDropOldest:
+		return true
+	}
+
+	return false
+}
+
+// asyncWriter queues written messages and writes them into the target from its own goroutine
+type asyncWriter struct {
+	pending int64
+	mu      sync.RWMutex
+	out     io.Writer
+	closer  io.Closer
+	queue   chan []byte
+	done    chan struct{}
+	closed  bool
+	policy  string
+	timeout time.Duration
+	stats   *counters
+	log     *Log
+}
+
+func newAsyncWriter(out io.Writer, closer io.Closer, size int, policy string, timeout time.Duration, l *Log) *asyncWriter {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+
+	w := &asyncWriter{
+		out:     out,
+		closer:  closer,
+		queue:   make(chan []byte, size),
+		done:    make(chan struct{}),
+		policy:  policy,
+		timeout: timeout,
+		stats:   &l.counters,
+		log:     l,
+	}
+	go w.run()
+
+	return w
+}
+
+func (w *asyncWriter) run() {
+	defer close(w.done)
+
+	for msg := range w.queue {
+		if _, err := w.out.Write(msg); err != nil {
+			internalErrorf(w.log, "failed to write into async logger: %s", err.Error())
+		}
+		atomic.AddInt64(&w.pending, -1)
+	}
+}
+
+// Write implements io.Writer interface, the message is copied and queued
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return 0, fmt.Errorf("async logger is closed")
+	}
+
+	msg := append([]byte(nil), p...)
+	atomic.AddInt64(&w.pending, 1)
+	switch w.policy {
+	case OverflowDropNewest:
+		select {
+		case w.queue <- msg:
+		default:
+			atomic.AddInt64(&w.pending, -1)
+			atomic.AddUint64(&w.stats.droppedNewest, 1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case w.queue <- msg:
+				return len(p), nil
+			default:
+			}
+
+			select {
+			case <-w.queue:
+				atomic.AddInt64(&w.pending, -1)
+				atomic.AddUint64(&w.stats.droppedOldest, 1)
+			default:
+			}
+		}
+	default:
+		if w.timeout <= 0 {
+			w.queue <- msg
+			break
+		}
+
+		t := time.NewTimer(w.timeout)
+		defer t.Stop()
+		select {
+		case w.queue <- msg:
+		case <-t.C:
+			atomic.AddInt64(&w.pending, -1)
+			atomic.AddUint64(&w.stats.droppedTimeout, 1)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close implements io.Closer interface, queued messages are written before the target is closed
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+
+	if w.closer == nil {
+		return nil
+	}
+
+	return w.closer.Close()
+}
+
+// idle reports whether all queued messages have been written
+func (w *asyncWriter) idle() bool {
+	return atomic.LoadInt64(&w.pending) == 0
+}
+
+// idlePoll is the interval WaitIdle checks the queues in
+const idlePoll = 5 * time.Millisecond
+
+// WaitIdle blocks until messages queued by async loggers have been written or ctx is done,
+// returning its error. It returns immediately when there are no async loggers.
+func (l *Log) WaitIdle(ctx context.Context) error {
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	var writers []*asyncWriter
+	for _, lg := range l.loggers {
+		writers = append(writers, lg.async...)
+	}
+	l.mu.RUnlock()
+
+	t := time.NewTicker(idlePoll)
+	defer t.Stop()
+
+	for _, w := range writers {
+		for !w.idle() {
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter blocks writes until released and records the written messages
+type gateWriter struct {
+	mu      sync.Mutex
+	lines   []string
+	entered chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{entered: make(chan struct{}), gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.entered) })
+	<-w.gate
+
+	w.mu.Lock()
+	w.lines = append(w.lines, strings.TrimSuffix(string(p), "\n"))
+	w.mu.Unlock()
+
+	return len(p), nil
+}
+
+func (w *gateWriter) written() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.lines...)
+}
+
+// saturate writes count messages into the async writer while its target is stalled, the first
+// message is held by the writing goroutine and the rest compete for the queue
+func saturate(t *testing.T, w *asyncWriter, out *gateWriter, count int) {
+	t.Helper()
+
+	w.Write([]byte("msg0\n"))
+	select {
+	case <-out.entered:
+	case <-time.After(time.Second):
+		t.Fatal("async logger did not start writing")
+	}
+
+	for i := 1; i < count; i++ {
+		w.Write([]byte(fmt.Sprintf("msg%d\n", i)))
+	}
+}
+
+func TestAsyncOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		written []string
+		newest  uint64
+		oldest  uint64
+	}{
+		{OverflowDropNewest, []string{"msg0", "msg1", "msg2"}, 7, 0},
+		{OverflowDropOldest, []string{"msg0", "msg8", "msg9"}, 0, 7},
+		{OverflowBlock, []string{"msg0", "msg1", "msg2", "msg3", "msg4", "msg5", "msg6", "msg7", "msg8", "msg9"}, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			l := &Log{}
+			out := newGateWriter()
+			w := newAsyncWriter(out, nil, 2, test.policy, 0, l)
+
+			if test.policy == OverflowBlock {
+				go func() {
+					<-out.entered
+					time.Sleep(20 * time.Millisecond)
+					close(out.gate)
+				}()
+			}
+			saturate(t, w, out, 10)
+			if test.policy != OverflowBlock {
+				close(out.gate)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.Join(out.written(), ","); got != strings.Join(test.written, ",") {
+				t.Errorf("written %s, expected %s", got, strings.Join(test.written, ","))
+			}
+			stats := l.Stats()
+			if stats.DroppedNewest != test.newest || stats.DroppedOldest != test.oldest {
+				t.Errorf("dropped newest %d and oldest %d, expected %d and %d", stats.DroppedNewest, stats.DroppedOldest, test.newest, test.oldest)
+			}
+		})
+	}
+}
+
+func TestAsyncEnqueueTimeout(t *testing.T) {
+	l := &Log{}
+	out := newGateWriter()
+	timeout := 10 * time.Millisecond
+	w := newAsyncWriter(out, nil, 2, OverflowBlock, timeout, l)
+
+	start := time.Now()
+	saturate(t, w, out, 5)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("writes into stalled logger took %s", elapsed)
+	}
+	close(out.gate)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(out.written(), ","); got != "msg0,msg1,msg2" {
+		t.Errorf("written %s, expected msg0,msg1,msg2", got)
+	}
+	if got := l.Stats().DroppedTimeout; got != 2 {
+		t.Errorf("dropped %d messages on timeout, expected 2", got)
+	}
+}
+
+func TestInvalidEnqueueTimeout(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{{LogType: "membuf", Severity: Information, Async: true, EnqueueTimeout: "soon"}}})
+	if err == nil {
+		l.Close()
+		t.Fatal("invalid enqueue timeout accepted")
+	}
+}
+
+// TestWaitIdle writes through async logger and asserts its output once the queue is drained
+func TestWaitIdle(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Async: true, BufferSize: 10})
+	for i := 0; i < 50; i++ {
+		l.Infof("message %d", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := l.WaitIdle(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := exportLines(l)
+	if len(lines) != 50 || !strings.HasSuffix(lines[49], "message 49") {
+		t.Errorf("got %d lines after WaitIdle, want 50", len(lines))
+	}
+}
+
+func TestWaitIdleCancelled(t *testing.T) {
+	out := newGateWriter()
+	if err := RegisterTarget("gate", func(cfg LoggerConfig) (io.WriteCloser, error) { return nopCloser{out}, nil }); err != nil {
+		t.Fatal(err)
+	}
+	l := newTestLog(t, LoggerConfig{LogType: "gate", Severity: Information, Async: true})
+	// the gate is opened before the log is closed
+	t.Cleanup(func() { close(out.gate) })
+	l.Info("stalled")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.WaitIdle(ctx); err != context.DeadlineExceeded {
+		t.Errorf("WaitIdle returned %v, want deadline exceeded", err)
+	}
+}
+
+func TestWaitIdleSync(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+	l.Info("message")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.WaitIdle(ctx); err != nil {
+		t.Errorf("WaitIdle without async loggers returned %v", err)
+	}
+}
+
+// nopCloser adds no-op Close to writer
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
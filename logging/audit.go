@@ -0,0 +1,48 @@
+package logging
+
+import "strings"
+
+// AuditLog writes audit events with stable schema: audit, actor, action, resource
+// and outcome fields followed by optional extra fields
+type AuditLog struct {
+	log      *Log
+	severity LogSeverity
+}
+
+// NewAuditLog creates audit log writing events with the given severity into the log
+func NewAuditLog(l *Log, severity LogSeverity) *AuditLog {
+	return &AuditLog{log: l, severity: severity}
+}
+
+// Audit writes audit event. Extra fields cannot override the required ones.
+// Event missing any of the required fields is written as error with audit_error field
+// listing the missing fields.
+func (a *AuditLog) Audit(actor, action, resource, outcome string, extra map[string]interface{}) {
+	fields := mergeFields(extra, map[string]interface{}{
+		"audit":    true,
+		"actor":    actor,
+		"action":   action,
+		"resource": resource,
+		"outcome":  outcome,
+	})
+
+	var missing []string
+	for _, f := range []struct{ name, value string }{
+		{"actor", actor},
+		{"action", action},
+		{"resource", resource},
+		{"outcome", outcome},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			missing = append(missing, f.name)
+		}
+	}
+
+	if len(missing) > 0 {
+		fields["audit_error"] = "missing " + strings.Join(missing, ", ")
+		a.log.WithFields(fields).Error("invalid audit event")
+		return
+	}
+
+	a.log.WithFields(fields).writeMessage(a.severity, "audit event")
+}
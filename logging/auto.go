@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"os"
+	"strings"
+)
+
+// FormatAuto writes colored text when the logger writes into a terminal and JSON otherwise
+// (e.g. when the output is piped into jq). The choice is made when the logger is set up.
+const FormatAuto = "auto"
+
+// autoFormatEnv overrides the choice of auto format when set to text, json or text+json
+const autoFormatEnv = "LOG_FORMAT"
+
+// severityColors are ANSI escape sequences coloring severities in text written into terminal
+var severityColors = map[LogSeverity]string{
+	Fatal:       "\x1b[1;31m",
+	Error:       "\x1b[31m",
+	Warning:     "\x1b[33m",
+	Information: "\x1b[32m",
+	Debug:       "\x1b[36m",
+	Verbose:     "\x1b[90m",
+}
+
+const colorReset = "\x1b[0m"
+
+// terminal reports whether the file is a terminal, it is replaced by tests
+var terminal = isTerminal
+
+// resolveAutoFormat returns format and coloring of auto format logger of the type. LOG_FORMAT
+// environment variable takes precedence and NO_COLOR disables colors. Console logger writes
+// colored text only when both standard output and standard error are terminals.
+func resolveAutoFormat(logType LogType) (string, bool) {
+	if env := strings.ToLower(os.Getenv(autoFormatEnv)); env == FormatText || env == FormatJSON || env == FormatTextJSON {
+		return env, false
+	}
+
+	var outs []*os.File
+	switch logType {
+	case Screen:
+		outs = []*os.File{stdout()}
+	case Console:
+		outs = []*os.File{stdout(), stderr()}
+	case Stderr:
+		outs = []*os.File{stderr()}
+	}
+
+	if len(outs) == 0 {
+		return FormatJSON, false
+	}
+	for _, out := range outs {
+		if !terminal(out) {
+			return FormatJSON, false
+		}
+	}
+
+	_, noColor := os.LookupEnv("NO_COLOR")
+
+	return FormatText, !noColor
+}
+
+// colored wraps severity text in its color when the logger colors output, colors set by
+// SetSeverityColors take precedence over the defaults
+func (l *Logger) colored(severity LogSeverity, text string) string {
+	if !l.color {
+		return text
+	}
+
+	if l.colors != nil {
+		return l.colors[severity] + text + colorReset
+	}
+
+	return severityColors[severity] + text + colorReset
+}
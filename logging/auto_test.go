@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if isTerminal(w) {
+		t.Error("pipe is terminal")
+	}
+
+	if null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		defer null.Close()
+		if isTerminal(null) {
+			t.Errorf("%s is terminal", os.DevNull)
+		}
+	}
+}
+
+func TestResolveAutoFormat(t *testing.T) {
+	prevTerminal := terminal
+	defer func() { terminal = prevTerminal }()
+	unsetEnv(t, autoFormatEnv)
+	unsetEnv(t, "NO_COLOR")
+
+	for _, tc := range []struct {
+		name      string
+		logType   LogType
+		terminals map[*os.File]bool
+		env       map[string]string
+		format    string
+		color     bool
+	}{
+		{"screen on terminal", Screen, map[*os.File]bool{os.Stdout: true}, nil, FormatText, true},
+		{"screen piped", Screen, nil, nil, FormatJSON, false},
+		{"stderr on terminal", Stderr, map[*os.File]bool{os.Stderr: true}, nil, FormatText, true},
+		{"stderr checks stderr", Stderr, map[*os.File]bool{os.Stdout: true}, nil, FormatJSON, false},
+		{"console on terminal", Console, map[*os.File]bool{os.Stdout: true, os.Stderr: true}, nil, FormatText, true},
+		{"console with stderr redirected", Console, map[*os.File]bool{os.Stdout: true}, nil, FormatJSON, false},
+		{"console with stdout piped", Console, map[*os.File]bool{os.Stderr: true}, nil, FormatJSON, false},
+		{"file", File, map[*os.File]bool{os.Stdout: true, os.Stderr: true}, nil, FormatJSON, false},
+		{"no color", Screen, map[*os.File]bool{os.Stdout: true}, map[string]string{"NO_COLOR": ""}, FormatText, false},
+		{"env override", Screen, map[*os.File]bool{os.Stdout: true}, map[string]string{autoFormatEnv: "JSON"}, FormatJSON, false},
+		{"env text when piped", Screen, nil, map[string]string{autoFormatEnv: "text"}, FormatText, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			terminal = func(f *os.File) bool { return tc.terminals[f] }
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tc.env {
+					os.Unsetenv(k)
+				}
+			}()
+
+			format, color := resolveAutoFormat(tc.logType)
+			if format != tc.format || color != tc.color {
+				t.Errorf("got %s, color %v, want %s, color %v", format, color, tc.format, tc.color)
+			}
+		})
+	}
+}
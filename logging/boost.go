@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// BoostSeverity raises severity of all loggers to s for the duration d and restores
+// previous severities afterwards. Loggers already more verbose than s are not changed.
+// Calling it again while the boost is active restarts the timer instead of stacking.
+func (l *Log) BoostSeverity(s LogSeverity, d time.Duration) error {
+	if !validSeverity(s) {
+		return fmt.Errorf("%d is invalid severity", s)
+	}
+	if d <= 0 {
+		return fmt.Errorf("boost duration must be positive")
+	}
+
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.boost != nil {
+		l.boost.Stop()
+	} else {
+		l.boostPrior = make(map[*Logger]LogSeverity, len(l.loggers))
+		for _, lg := range l.loggers {
+			l.boostPrior[lg] = lg.severity
+		}
+	}
+
+	l.boostSeverity = s
+	for _, lg := range l.loggers {
+		if prior, ok := l.boostPrior[lg]; ok {
+			lg.severity = prior
+			if prior < s {
+				lg.severity = s
+			}
+		}
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if l.boost != timer {
+			return
+		}
+		l.restoreBoost()
+	})
+	l.boost = timer
+
+	return nil
+}
+
+// restoreBoost restores severities stored by BoostSeverity, must be called with l.mu locked
+func (l *Log) restoreBoost() {
+	for _, lg := range l.loggers {
+		if prior, ok := l.boostPrior[lg]; ok {
+			lg.severity = prior
+		}
+	}
+
+	l.boost = nil
+	l.boostPrior = nil
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoostSeverity(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+	if err := l.BoostSeverity(Debug, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("boosted")
+	time.Sleep(100 * time.Millisecond)
+	l.Debug("restored")
+
+	if lines := exportLines(l); len(lines) != 1 {
+		t.Errorf("got %q, want the boosted message only", lines)
+	}
+}
+
+func TestInvalidBoost(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+	for _, tc := range []struct {
+		name     string
+		severity LogSeverity
+		duration time.Duration
+	}{
+		{"severity", 35, time.Second},
+		{"zero duration", Debug, 0},
+		{"negative duration", Debug, -time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := l.BoostSeverity(tc.severity, tc.duration); err == nil {
+				t.Error("invalid boost accepted")
+			}
+			l.Debug("not boosted")
+			if lines := exportLines(l); len(lines) != 0 {
+				t.Errorf("got %q", lines)
+			}
+		})
+	}
+}
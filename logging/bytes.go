@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+// Encodings of byte slice field values
+const (
+	// BytesHex renders byte slices as hexadecimal string
+	BytesHex = "hex"
+	// BytesBase64 renders byte slices as standard base64 string
+	BytesBase64 = "base64"
+)
+
+const defaultBytesLimit = 32
+
+// bytesRendering holds encoding and number of rendered bytes of byte slice field values
+type bytesRendering struct {
+	encoding string
+	limit    int
+}
+
+var bytesConfig atomic.Value
+
+func init() {
+	bytesConfig.Store(bytesRendering{encoding: BytesHex, limit: defaultBytesLimit})
+}
+
+// SetBytesRendering sets how byte slice field values are written: encoding (hex by default
+// or base64) of at most limit leading bytes (32 by default, 0 renders all of them) followed
+// by the length, e.g. "0a1b2c... (100 bytes)", so binary data does not garble the log.
+func SetBytesRendering(encoding string, limit int) error {
+	switch encoding {
+	case BytesHex, BytesBase64:
+	default:
+		return fmt.Errorf("%s is invalid bytes encoding", encoding)
+	}
+
+	if limit < 0 {
+		return fmt.Errorf("%d is invalid bytes limit", limit)
+	}
+
+	bytesConfig.Store(bytesRendering{encoding: encoding, limit: limit})
+
+	return nil
+}
+
+// renderBytes renders byte slice field value according to SetBytesRendering
+func renderBytes(b []byte) string {
+	cfg := bytesConfig.Load().(bytesRendering)
+
+	preview, suffix := b, ""
+	if cfg.limit > 0 && len(b) > cfg.limit {
+		preview, suffix = b[:cfg.limit], "..."
+	}
+
+	var s string
+	if cfg.encoding == BytesBase64 {
+		s = base64.StdEncoding.EncodeToString(preview)
+	} else {
+		s = hex.EncodeToString(preview)
+	}
+
+	return fmt.Sprintf("%s%s (%d bytes)", s, suffix, len(b))
+}
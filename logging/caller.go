@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// callerKey is the field carrying source location of the logging call
+const callerKey = "caller"
+
+// packagePath is the import path of this package, its frames are skipped when looking for caller
+var packagePath = reflect.TypeOf(Log{}).PkgPath()
+
+// callerSite returns location (directory/file.go:line) of the first stack frame outside of this
+// package and the standard log package, so the same location is found regardless of the method
+// used to log. Further skip frames are skipped after it, e.g. frames of logging wrappers.
+func callerSite(skip int) string {
+	pcs := make([]uintptr, 32+skip)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, packagePath+".") && !strings.HasPrefix(f.Function, "log.") {
+			if skip == 0 {
+				return path.Join(path.Base(path.Dir(f.File)), path.Base(f.File)) + ":" + strconv.Itoa(f.Line)
+			}
+			skip--
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// WithCallerSkip returns log reporting caller (ShowCaller) n frames above the logging call,
+// so that helpers wrapping the log report location of their callers, e.g. WithCallerSkip(1)
+// in a function logging on behalf of its caller. Skips of logs derived from it add up.
+func (l *Log) WithCallerSkip(n int) *Log {
+	if l == nil {
+		return nil
+	}
+
+	d := l.WithFields(nil)
+	d.callerSkip += n
+	if d.callerSkip < 0 {
+		d.callerSkip = 0
+	}
+
+	return d
+}
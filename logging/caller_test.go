@@ -0,0 +1,73 @@
+package logging_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mafalt/go-logging/logging"
+)
+
+// Caller tests are external to the package, its own frames are never reported as caller
+
+// nextLine returns location of the line following its call as the log reports callers
+func nextLine() string {
+	_, file, n, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), n+1)
+}
+
+// warn is a logging wrapper reporting location of its caller
+func warn(l *logging.Log, msg string) {
+	l.WithCallerSkip(1).Warning(msg)
+}
+
+// warnHere is a logging wrapper without caller skip, it returns location it is reported at
+func warnHere(l *logging.Log, msg string) string {
+	site := nextLine()
+	l.Warning(msg)
+	return site
+}
+
+func lastCaller(t *testing.T, l *logging.Log) string {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(string(l.Export())), "\n")
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &m); err != nil {
+		t.Fatal(err)
+	}
+	caller, _ := m["caller"].(string)
+
+	return caller
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	l := &logging.Log{}
+	err := l.SetupLoggers(logging.LogConfig{Loggers: []logging.LoggerConfig{
+		{LogType: "membuf", Severity: logging.Information, Format: logging.FormatJSON, ShowCaller: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	want := nextLine()
+	l.Warning("direct")
+	if got := lastCaller(t, l); got != want {
+		t.Errorf("direct call reported %s, want %s", got, want)
+	}
+
+	want = nextLine()
+	warn(l, "wrapped")
+	if got := lastCaller(t, l); got != want {
+		t.Errorf("wrapped call reported %s, want %s", got, want)
+	}
+
+	want = warnHere(l, "unskipped")
+	if got := lastCaller(t, l); got != want {
+		t.Errorf("wrapper without skip reported %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+)
+
+// LogBuffer collects messages captured by StartCapture as text lines
+type LogBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer interface
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// String returns captured messages
+func (b *LogBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// Lines returns captured messages one per item
+func (b *LogBuffer) Lines() []string {
+	s := strings.TrimSuffix(b.String(), "\n")
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
+
+// StartCapture redirects messages of all severities from the loggers into the returned buffer
+// until StopCapture is called. Captures nest, the most recent one receives the messages.
+func (l *Log) StartCapture() *LogBuffer {
+	buf := &LogBuffer{}
+
+	l = l.base()
+	if l == nil {
+		return buf
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.captures = append(l.captures, &Logger{
+		rawLogger: log.New(buf, "", logFlags),
+		severity:  Verbose,
+		separator: " ",
+	})
+
+	return buf
+}
+
+// StopCapture ends the most recent capture, messages are written into the buffer
+// of the previous capture or into the loggers again
+func (l *Log) StopCapture() {
+	l = l.base()
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.captures) > 0 {
+		l.captures = l.captures[:len(l.captures)-1]
+	}
+}
+
+// targets returns loggers messages are written into, the current capture if any,
+// must be called with l.mu locked for reading
+func (l *Log) targets() []*Logger {
+	if n := len(l.captures); n > 0 {
+		return l.captures[n-1:]
+	}
+
+	return l.loggers
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// clock caches current time refreshed by a background ticker, so writing a message
+// does not call time.Now
+type clock struct {
+	now  atomic.Value
+	stop chan struct{}
+}
+
+func newClock(interval time.Duration) *clock {
+	c := &clock{stop: make(chan struct{})}
+	c.now.Store(time.Now())
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case t := <-ticker.C:
+				c.now.Store(t)
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+
+	return c
+}
+
+func (c *clock) Now() time.Time {
+	return c.now.Load().(time.Time)
+}
+
+func (c *clock) Stop() {
+	close(c.stop)
+}
+
+// parseClockInterval validates ClockInterval, empty interval disables the cached clock
+func parseClockInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s is invalid clock interval: %s", s, err.Error())
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s is invalid clock interval", s)
+	}
+
+	return d, nil
+}
+
+// now returns time of the message being written, it must be called on the root log
+// with l.mu locked for reading
+func (l *Log) now() time.Time {
+	if l.clock != nil {
+		return l.clock.Now()
+	}
+
+	return time.Now()
+}
+
+// formattedTime is time rendered in text layout
+type formattedTime struct {
+	t    time.Time
+	text string
+}
+
+// lastTextTime keeps the most recently formatted time, messages timestamped by the cached
+// clock share the same time until it is refreshed, so they reuse its text
+var lastTextTime atomic.Value
+
+// formatTextTime renders t in text layout
+func formatTextTime(t time.Time) string {
+	if last, ok := lastTextTime.Load().(*formattedTime); ok && last.t.Equal(t) && last.t.Location() == t.Location() {
+		return last.text
+	}
+
+	text := t.Format(textTimeLayout)
+	lastTextTime.Store(&formattedTime{t: t, text: text})
+
+	return text
+}
+
+// timeText renders time header of text lines when the raw logger does not write it, it is
+// preceded by prefix when the line starts with priority
+func (l *Logger) timeText(t time.Time) string {
+	if l.priority {
+		return l.config.Prefix + formatTextTime(t) + " "
+	}
+
+	if !l.ownTime {
+		return ""
+	}
+
+	return formatTextTime(t) + " "
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseClockInterval(t *testing.T) {
+	for _, tc := range []struct {
+		interval string
+		want     time.Duration
+		ok       bool
+	}{
+		{"", 0, true},
+		{"5ms", 5 * time.Millisecond, true},
+		{"0s", 0, false},
+		{"-1ms", 0, false},
+		{"often", 0, false},
+	} {
+		got, err := parseClockInterval(tc.interval)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("parseClockInterval(%q) = %s, %v", tc.interval, got, err)
+		}
+	}
+}
+
+func TestClockRefreshes(t *testing.T) {
+	c := newClock(time.Millisecond)
+	defer c.Stop()
+
+	first := c.Now()
+	deadline := time.Now().Add(2 * time.Second)
+	for !c.Now().After(first) {
+		if time.Now().After(deadline) {
+			t.Fatal("cached clock was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func BenchmarkClockNow(b *testing.B) {
+	b.Run("time.Now", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			time.Now()
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		c := newClock(5 * time.Millisecond)
+		defer c.Stop()
+
+		for i := 0; i < b.N; i++ {
+			c.Now()
+		}
+	})
+}
+
+func BenchmarkClockInterval(b *testing.B) {
+	if err := RegisterTarget("discard", func(cfg LoggerConfig) (io.WriteCloser, error) { return nopCloser{ioutil.Discard}, nil }); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bc := range []struct {
+		name, interval string
+	}{
+		{"time.Now", ""},
+		{"cached", "5ms"},
+	} {
+		for _, format := range []string{FormatText, FormatJSON} {
+			b.Run(bc.name+"/"+format, func(b *testing.B) {
+				l := &Log{}
+				err := l.SetupLoggers(LogConfig{
+					Loggers:       []LoggerConfig{{LogType: "discard", Severity: Information, Format: format}},
+					ClockInterval: bc.interval,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				defer l.Close()
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						l.Info("message")
+					}
+				})
+			})
+		}
+	}
+}
+
+func TestFormatTextTime(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+	east := time.FixedZone("east", 3600)
+
+	for _, tc := range []struct {
+		t    time.Time
+		want string
+	}{
+		{now, "2024/05/06 07:08:09.123456"},
+		{now, "2024/05/06 07:08:09.123456"},
+		{now.In(east), "2024/05/06 08:08:09.123456"},
+		{now.Add(time.Millisecond), "2024/05/06 07:08:09.124456"},
+	} {
+		if got := formatTextTime(tc.t); got != tc.want {
+			t.Errorf("formatTextTime(%s) = %s, want %s", tc.t, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// colorNames are SGR codes of named colors and attributes accepted by SetSeverityColors
+var colorNames = map[string]string{
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+	"grey":      "90",
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+}
+
+// SetSeverityColors overrides colors of severities in colored text output (auto format writing
+// into terminal), severities missing in the map keep the default colors and nil map restores
+// all of them. Color is either ANSI SGR code (e.g. "35" or "1;33", escape sequence "\x1b[35m"
+// is accepted as well) or names of colors and attributes separated by spaces (e.g. "magenta"
+// or "bold red"; black, red, green, yellow, blue, magenta, cyan, white, gray, bold, dim, italic
+// and underline). Colors apply to loggers set up later too, output without colors is not
+// affected.
+func (l *Log) SetSeverityColors(colors map[LogSeverity]string) error {
+	l = l.base()
+	if l == nil {
+		return fmt.Errorf("unable to set colors of nil log")
+	}
+
+	var resolved map[LogSeverity]string
+	if colors != nil {
+		resolved = make(map[LogSeverity]string, len(severityColors))
+		for severity, c := range severityColors {
+			resolved[severity] = c
+		}
+
+		for severity, color := range colors {
+			if !validSeverity(severity) {
+				return fmt.Errorf("%d is invalid severity", severity)
+			}
+
+			seq, err := parseColor(color)
+			if err != nil {
+				return err
+			}
+			resolved[severity] = seq
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.colors = resolved
+	l.applyColors()
+
+	return nil
+}
+
+// applyColors hands colors of the log to its loggers, it must be called with the lock held
+func (l *Log) applyColors() {
+	for _, lg := range l.loggers {
+		lg.colors = l.colors
+	}
+}
+
+// parseColor returns ANSI escape sequence of color given by SGR code or names
+func parseColor(color string) (string, error) {
+	code := strings.TrimSpace(color)
+	if code == "" {
+		return "", fmt.Errorf("color is not set")
+	}
+
+	if strings.HasPrefix(code, "\x1b[") && strings.HasSuffix(code, "m") {
+		if seq := strings.TrimSuffix(strings.TrimPrefix(code, "\x1b["), "m"); validColorCode(seq) {
+			return code, nil
+		}
+		return "", fmt.Errorf("%q is invalid color", color)
+	}
+
+	if validColorCode(code) {
+		return "\x1b[" + code + "m", nil
+	}
+
+	names := strings.Fields(strings.ToLower(code))
+	codes := make([]string, 0, len(names))
+	for _, name := range names {
+		c, ok := colorNames[name]
+		if !ok {
+			return "", fmt.Errorf("%q is invalid color", color)
+		}
+		codes = append(codes, c)
+	}
+
+	return "\x1b[" + strings.Join(codes, ";") + "m", nil
+}
+
+// validColorCode reports whether code is sequence of SGR parameters separated by semicolons
+func validColorCode(code string) bool {
+	for _, p := range strings.Split(code, ";") {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 255 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	for _, tc := range []struct {
+		color string
+		want  string
+		err   bool
+	}{
+		{color: "35", want: "\x1b[35m"},
+		{color: "1;33", want: "\x1b[1;33m"},
+		{color: "\x1b[2m", want: "\x1b[2m"},
+		{color: "magenta", want: "\x1b[35m"},
+		{color: "dim", want: "\x1b[2m"},
+		{color: "Bold Red", want: "\x1b[1;31m"},
+		{color: "dim gray", want: "\x1b[2;90m"},
+		{color: "", err: true},
+		{color: "pink", err: true},
+		{color: "256", err: true},
+		{color: "\x1b[dimm", err: true},
+	} {
+		got, err := parseColor(tc.color)
+		if tc.err {
+			if err == nil {
+				t.Errorf("parseColor(%q) = %q, want error", tc.color, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("parseColor(%q) = %q, %v, want %q", tc.color, got, err, tc.want)
+		}
+	}
+}
+
+func TestSetSeverityColors(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+	// colors apply only to colored output, which membuf does not produce on its own
+	l.loggers[0].color = true
+
+	if err := l.SetSeverityColors(map[LogSeverity]string{Warning: "magenta", Information: "dim"}); err != nil {
+		t.Fatal(err)
+	}
+	l.Warning("warning")
+	l.Info("info")
+	l.Error("error")
+
+	lines := exportLines(l)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	for i, want := range []string{"\x1b[35mWARNING\x1b[0m", "\x1b[2mINFO   \x1b[0m", severityColors[Error] + "ERROR  \x1b[0m"} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %q does not contain %q", lines[i], want)
+		}
+	}
+
+	for _, colors := range []map[LogSeverity]string{{Warning: "pink"}, {55: "red"}} {
+		if err := l.SetSeverityColors(colors); err == nil {
+			t.Errorf("colors %v were accepted", colors)
+		}
+	}
+}
+
+func TestSetSeverityColorsWithoutColor(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+
+	if err := l.SetSeverityColors(map[LogSeverity]string{Information: "red"}); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("plain")
+
+	if lines := exportLines(l); len(lines) != 1 || strings.Contains(lines[0], "\x1b[") {
+		t.Errorf("uncolored output %q contains escape sequence", lines)
+	}
+}
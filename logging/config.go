@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// plainLogConfig has the same fields as LogConfig but none of its methods
+type plainLogConfig LogConfig
+
+func mergeLoggerKeys(cfg *plainLogConfig, alt []LoggerConfig) error {
+	if len(alt) == 0 {
+		return nil
+	}
+
+	if len(cfg.Loggers) > 0 {
+		return fmt.Errorf("only one of logger and loggers keys may be set")
+	}
+	cfg.Loggers = alt
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface accepting both logger and loggers keys
+func (c *LogConfig) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		plainLogConfig
+		Loggers []LoggerConfig `json:"loggers"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if err := mergeLoggerKeys(&raw.plainLogConfig, raw.Loggers); err != nil {
+		return err
+	}
+	*c = LogConfig(raw.plainLogConfig)
+
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface accepting both logger and loggers keys
+func (c *LogConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw struct {
+		plainLogConfig `yaml:",inline"`
+		Logger         []LoggerConfig `yaml:"logger"`
+	}
+
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	if err := mergeLoggerKeys(&raw.plainLogConfig, raw.Logger); err != nil {
+		return err
+	}
+	*c = LogConfig(raw.plainLogConfig)
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestLogConfigKeys(t *testing.T) {
+	want := LogConfig{
+		Loggers: []LoggerConfig{
+			{LogType: "file", Severity: Information, Path: "/var/log/app.log"},
+			{LogType: "screen", Severity: Error},
+		},
+		TimeZone: "UTC",
+	}
+
+	for _, tc := range []struct {
+		name   string
+		decode func([]byte, interface{}) error
+		data   string
+		err    bool
+	}{
+		{"json logger", json.Unmarshal, `{"timeZone":"UTC","logger":[
+			{"logType":"file","severity":40,"path":"/var/log/app.log"},{"logType":"screen","severity":20}]}`, false},
+		{"json loggers", json.Unmarshal, `{"timeZone":"UTC","loggers":[
+			{"logType":"file","severity":40,"path":"/var/log/app.log"},{"logType":"screen","severity":20}]}`, false},
+		{"json both keys", json.Unmarshal, `{"logger":[{"logType":"file"}],"loggers":[{"logType":"screen"}]}`, true},
+		{"yaml loggers", yaml.Unmarshal, `
+timeZone: UTC
+loggers:
+  - logType: file
+    severity: 40
+    path: /var/log/app.log
+  - logType: screen
+    severity: 20
+`, false},
+		{"yaml logger", yaml.Unmarshal, `
+timeZone: UTC
+logger:
+  - logType: file
+    severity: 40
+    path: /var/log/app.log
+  - logType: screen
+    severity: 20
+`, false},
+		{"yaml both keys", yaml.Unmarshal, `
+logger:
+  - logType: file
+loggers:
+  - logType: screen
+`, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg LogConfig
+			err := tc.decode([]byte(tc.data), &cfg)
+			if tc.err {
+				if err == nil {
+					t.Error("config with both keys was accepted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("got %+v, want %+v", cfg, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package logging
+
+// consoleSplit is the least severe severity console logger writes into standard error
+const consoleSplit = Warning
+
+// setupConsole creates raw loggers of console logger, messages of consoleSplit severity
+// and more severe are written into standard error and the others into standard output
+func (l *Log) setupConsole(lg *Logger, item LoggerConfig) error {
+	out := &Logger{logType: Screen, format: lg.format, dryRun: lg.dryRun, enqueueTimeout: lg.enqueueTimeout}
+	w, c, err := l.newWriter(out, item)
+	if err != nil {
+		return err
+	}
+
+	errOut := &Logger{logType: Stderr, format: lg.format, dryRun: lg.dryRun, enqueueTimeout: lg.enqueueTimeout}
+	ew, ec, err := l.newWriter(errOut, item)
+	if err != nil {
+		multiCloser{c}.Close()
+		return err
+	}
+
+	lg.rawLogger = lg.newRawLogger(w)
+	lg.errLogger = lg.newRawLogger(ew)
+	lg.screen, lg.errScreen = out.screen, errOut.screen
+	lg.async = append(out.async, errOut.async...)
+	lg.out = multiCloser{c, ec}
+
+	return nil
+}
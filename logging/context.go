@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   []func(ctx context.Context) map[string]interface{}
+)
+
+// RegisterContextExtractor registers function returning fields extracted from context by
+// WithContext, it is used by optional integrations (e.g. package
+// github.com/mafalt/go-logging/logging/otel). Fields of later registered extractors override
+// the earlier.
+func RegisterContextExtractor(fn func(ctx context.Context) map[string]interface{}) {
+	if fn == nil {
+		return
+	}
+
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+
+	contextExtractors = append(contextExtractors, fn)
+}
+
+// WithContext returns log adding fields extracted from the context to every message by
+// extractors registered by RegisterContextExtractor, e.g. trace_id and span_id of the active
+// span when package github.com/mafalt/go-logging/logging/otel is imported (it is a separate
+// module, so applications not using it do not depend on OpenTelemetry).
+// It returns the log itself when there is nothing to extract.
+func (l *Log) WithContext(ctx context.Context) *Log {
+	if l == nil {
+		return nil
+	}
+
+	contextExtractorsMu.RLock()
+	extractors := contextExtractors
+	contextExtractorsMu.RUnlock()
+
+	var fields map[string]interface{}
+	for _, extract := range extractors {
+		if f := extract(ctx); len(f) > 0 {
+			fields = mergeFields(fields, f)
+		}
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.WithFields(fields)
+}
+
+// WithDeadline returns log adding deadline of the context and time remaining until it
+// (timeout_remaining, negative once the deadline passed) to every message, e.g. to log
+// when an operation starts how close it is to timing out. The remaining time is measured
+// when WithDeadline is called. It returns the log itself when the context has no deadline.
+func (l *Log) WithDeadline(ctx context.Context) *Log {
+	if l == nil {
+		return nil
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return l
+	}
+
+	return l.WithFields(map[string]interface{}{
+		"deadline":          deadline.Format(jsonTimeLayout),
+		"timeout_remaining": time.Until(deadline),
+	})
+}
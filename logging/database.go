@@ -0,0 +1,273 @@
+//go:build database
+// +build database
+
+package logging
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// databaseFlushInterval is the longest time inserted rows wait for their batch to fill up
+const databaseFlushInterval = time.Second
+
+// databaseBacklog is the number of batches kept while inserts fail, older rows are dropped
+const databaseBacklog = 10
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func init() {
+	registerJSONTarget("database", newDatabaseWriter)
+}
+
+// databaseRow is a log message decoded from its JSON line
+type databaseRow struct {
+	time     string
+	severity int
+	message  string
+	fields   string
+}
+
+// databaseWriter inserts messages written in JSON format (the default format of database
+// loggers, other formats are rejected) into a database table with columns time, severity,
+// message and fields (JSON object) in batches. The driver given
+// by Protocol must be imported by the program, Address is its data source name and Path
+// the table name (logs by default). Table is created when it does not exist. MaxAge and
+// MaxRows prune old rows after each batch. While inserts fail (e.g. the database is down)
+// at most 10 batches of rows are kept, the oldest rows are dropped and counted in Stats
+// as DroppedBacklog. Statements use ? placeholders and rowid as written for SQLite.
+type databaseWriter struct {
+	mu      sync.Mutex
+	db      *sql.DB
+	table   string
+	keys    FieldKeys
+	batch   []databaseRow
+	size    int
+	stats   *counters
+	maxRows int
+	maxAge  time.Duration
+	closing bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newDatabaseWriter(cfg LoggerConfig) (io.WriteCloser, error) {
+	if strings.ToLower(cfg.Format) != FormatJSON {
+		return nil, fmt.Errorf("database logger supports only json format, %s is set", cfg.Format)
+	}
+
+	table := cfg.Path
+	if table == "" {
+		table = "logs"
+	}
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("%s is invalid table name", table)
+	}
+
+	var maxAge time.Duration
+	if cfg.MaxAge != "" {
+		var err error
+		if maxAge, err = time.ParseDuration(cfg.MaxAge); err != nil {
+			return nil, fmt.Errorf("%s is invalid maximum age: %s", cfg.MaxAge, err.Error())
+		}
+	}
+
+	keys, err := resolveFieldKeys(cfg.FieldKeys, strings.ToLower(cfg.SeverityEncoding), cfg.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(cfg.Protocol, cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS " + table +
+		" (time TEXT NOT NULL, severity INTEGER NOT NULL, message TEXT NOT NULL, fields TEXT)")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create table %s: %s", table, err.Error())
+	}
+
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+
+	w := &databaseWriter{
+		db:      db,
+		table:   table,
+		keys:    keys,
+		size:    size,
+		maxRows: cfg.MaxRows,
+		maxAge:  maxAge,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+
+	return w, nil
+}
+
+// run flushes incomplete batch periodically
+func (w *databaseWriter) run() {
+	defer close(w.done)
+
+	t := time.NewTicker(databaseFlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			w.mu.Lock()
+			if err := w.flush(); err != nil {
+				internalErrorf(nil, "failed to insert log messages into %s: %s", w.table, err.Error())
+			}
+			w.mu.Unlock()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// decode converts JSON line into row, fields other than the standard keys are kept as JSON
+func (w *databaseWriter) decode(p []byte) (databaseRow, error) {
+	e, err := decodeJSON(p, w.keys)
+	if err != nil {
+		return databaseRow{}, err
+	}
+
+	row := databaseRow{severity: int(e.severity), message: e.message}
+	if !e.time.IsZero() {
+		row.time = e.time.UTC().Format(jsonTimeLayout)
+	}
+	if len(e.fields) > 0 {
+		b, err := json.Marshal(e.fields)
+		if err != nil {
+			return databaseRow{}, err
+		}
+		row.fields = string(b)
+	}
+
+	return row, nil
+}
+
+// Write implements io.Writer interface, the row is inserted once the batch is full
+func (w *databaseWriter) Write(p []byte) (int, error) {
+	row, err := w.decode(p)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode log message: %s", err.Error())
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.db == nil {
+		return 0, fmt.Errorf("database logger is closed")
+	}
+
+	w.batch = append(w.batch, row)
+	if over := len(w.batch) - w.size*databaseBacklog; over > 0 {
+		w.batch = append(w.batch[:0], w.batch[over:]...)
+		if w.stats != nil {
+			atomic.AddUint64(&w.stats.droppedBacklog, uint64(over))
+		}
+	}
+	if len(w.batch) >= w.size {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// flush inserts batched rows in a single transaction and prunes old rows,
+// must be called with w.mu locked
+func (w *databaseWriter) flush() error {
+	if len(w.batch) == 0 || w.db == nil {
+		return nil
+	}
+
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO " + w.table + " (time, severity, message, fields) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range w.batch {
+		var fields interface{}
+		if row.fields != "" {
+			fields = row.fields
+		}
+		if _, err := stmt.Exec(row.time, row.severity, row.message, fields); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	w.batch = w.batch[:0]
+
+	if w.maxAge > 0 {
+		cutoff := time.Now().Add(-w.maxAge).UTC().Format(jsonTimeLayout)
+		if _, err := tx.Exec("DELETE FROM "+w.table+" WHERE time < ?", cutoff); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if w.maxRows > 0 {
+		_, err := tx.Exec("DELETE FROM "+w.table+" WHERE rowid NOT IN (SELECT rowid FROM "+
+			w.table+" ORDER BY rowid DESC LIMIT ?)", w.maxRows)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// setStats implements statsUser interface
+func (w *databaseWriter) setStats(c *counters) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stats = c
+}
+
+// Close implements io.Closer interface, batched rows are inserted before the database is closed
+func (w *databaseWriter) Close() error {
+	w.mu.Lock()
+	if w.db == nil || w.closing {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closing = true
+	close(w.stop)
+	w.mu.Unlock()
+
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.flush()
+	if cerr := w.db.Close(); err == nil {
+		err = cerr
+	}
+	w.db = nil
+
+	return err
+}
@@ -0,0 +1,122 @@
+//go:build database
+// +build database
+
+package logging
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// failingDriver is database driver whose transactions fail while failing is set,
+// it counts inserted rows
+type failingDriver struct {
+	mu       sync.Mutex
+	failing  bool
+	inserted int
+}
+
+var testDriver = &failingDriver{}
+
+func init() {
+	sql.Register("logging-test", testDriver)
+}
+
+func (d *failingDriver) setFailing(failing bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.failing = failing
+}
+
+func (d *failingDriver) Open(name string) (driver.Conn, error) {
+	return &failingConn{d: d}, nil
+}
+
+type failingConn struct {
+	d *failingDriver
+}
+
+func (c *failingConn) Prepare(query string) (driver.Stmt, error) {
+	return &failingStmt{d: c.d, insert: len(query) > 6 && query[:6] == "INSERT"}, nil
+}
+
+func (c *failingConn) Close() error {
+	return nil
+}
+
+func (c *failingConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+
+	if c.d.failing {
+		return nil, fmt.Errorf("database is down")
+	}
+
+	return failingTx{}, nil
+}
+
+type failingTx struct{}
+
+func (failingTx) Commit() error   { return nil }
+func (failingTx) Rollback() error { return nil }
+
+type failingStmt struct {
+	d      *failingDriver
+	insert bool
+}
+
+func (s *failingStmt) Close() error  { return nil }
+func (s *failingStmt) NumInput() int { return -1 }
+
+func (s *failingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.insert {
+		s.d.mu.Lock()
+		s.d.inserted++
+		s.d.mu.Unlock()
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *failingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, fmt.Errorf("query is not supported")
+}
+
+func TestDatabaseBacklogWhileInsertsFail(t *testing.T) {
+	const size = 2
+	l := newTestLog(t, LoggerConfig{LogType: "database", Protocol: "logging-test", Severity: Information,
+		Format: FormatJSON, BufferSize: size})
+	silenceInternalLog(t)
+
+	testDriver.setFailing(true)
+	for i := 0; i < 100; i++ {
+		l.Infof("message %d", i)
+	}
+
+	w := l.loggers[0].out.(*databaseWriter)
+	w.mu.Lock()
+	backlog := len(w.batch)
+	w.mu.Unlock()
+	if backlog != size*databaseBacklog {
+		t.Errorf("backlog has %d rows, want %d", backlog, size*databaseBacklog)
+	}
+	if dropped := l.Stats().DroppedBacklog; dropped != 100-size*databaseBacklog {
+		t.Errorf("dropped %d rows, want %d", dropped, 100-size*databaseBacklog)
+	}
+
+	testDriver.setFailing(false)
+	l.Info("recovered")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	testDriver.mu.Lock()
+	defer testDriver.mu.Unlock()
+	if testDriver.inserted != size*databaseBacklog {
+		t.Errorf("inserted %d rows, want %d", testDriver.inserted, size*databaseBacklog)
+	}
+}
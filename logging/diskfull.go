@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Actions of file loggers when the disk is full
+const (
+	// DiskFullDrop drops messages which do not fit, warning once per incident
+	DiskFullDrop = "drop"
+	// DiskFullFallback writes messages which do not fit into standard error
+	DiskFullFallback = "fallback"
+	// DiskFullRetry pauses and retries the write which finds the disk full before dropping
+	// the message, messages written while the disk stays full are dropped at once
+	DiskFullRetry = "retry"
+)
+
+// diskFullRetries limits attempts made by a single write with retry action
+const diskFullRetries = 3
+
+const defaultDiskFullPause = time.Second
+
+// validDiskFull checks disk full action and pause of the logger
+func validDiskFull(item LoggerConfig) error {
+	switch strings.ToLower(item.DiskFullAction) {
+	case "", DiskFullDrop, DiskFullFallback, DiskFullRetry:
+	default:
+		return fmt.Errorf("%s is invalid disk full action", item.DiskFullAction)
+	}
+
+	if item.DiskFullPause != "" {
+		if d, err := time.ParseDuration(item.DiskFullPause); err != nil || d < 0 {
+			return fmt.Errorf("%s is invalid disk full pause", item.DiskFullPause)
+		}
+	}
+
+	return nil
+}
+
+// diskFullWriter applies disk full action of the logger to writes into its file. Warning is
+// written into the internal log when the disk fills up and again after the next successful
+// write, so each incident is reported once.
+type diskFullWriter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	path   string
+	action string
+	pause  time.Duration
+	full   bool
+	log    *Log
+}
+
+// diskFull wraps file target with the disk full action of the logger
+func (l *Log) diskFull(w io.Writer, item LoggerConfig) io.Writer {
+	pause := defaultDiskFullPause
+	if item.DiskFullPause != "" {
+		pause, _ = time.ParseDuration(item.DiskFullPause)
+	}
+
+	action := strings.ToLower(item.DiskFullAction)
+	if action == "" {
+		action = DiskFullDrop
+	}
+
+	return &diskFullWriter{out: w, path: item.Path, action: action, pause: pause, log: l}
+}
+
+// Write implements io.Writer interface
+func (w *diskFullWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n, err := w.out.Write(p)
+	if err == nil {
+		if w.full {
+			w.full = false
+			internalErrorf(w.log, "log file %s is writable again", w.path)
+		}
+		return n, nil
+	}
+	if !isDiskFull(err) {
+		return n, err
+	}
+
+	// retrying is done once per incident, so writes do not stall for the pauses while the
+	// disk stays full
+	if w.action == DiskFullRetry && !w.full {
+		for i := 0; i < diskFullRetries && err != nil && isDiskFull(err); i++ {
+			time.Sleep(w.pause)
+
+			var m int
+			m, err = w.out.Write(p[n:])
+			n += m
+		}
+		if err == nil {
+			return n, nil
+		}
+		if !isDiskFull(err) {
+			return n, err
+		}
+	}
+
+	if !w.full {
+		w.full = true
+		if w.action == DiskFullFallback {
+			internalErrorf(w.log, "disk of log file %s is full, writing messages into standard error", w.path)
+		} else {
+			internalErrorf(w.log, "disk of log file %s is full, dropping messages: %s", w.path, err.Error())
+		}
+	}
+
+	if w.action == DiskFullFallback {
+		return stderr().Write(p)
+	}
+
+	atomic.AddUint64(&w.log.counters.droppedDiskFull, 1)
+
+	return len(p), nil
+}
+
+// Sync implements syncer interface
+func (w *diskFullWriter) Sync() error {
+	if s, ok := w.out.(syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
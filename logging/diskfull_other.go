@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logging
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isDiskFull reports whether write failed because there is no space left on the device
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
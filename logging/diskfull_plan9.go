@@ -0,0 +1,14 @@
+//go:build plan9
+// +build plan9
+
+package logging
+
+import "strings"
+
+// isDiskFull reports whether write failed because the file system is full, Plan 9 reports
+// errors only by their text
+func isDiskFull(err error) bool {
+	msg := err.Error()
+
+	return strings.Contains(msg, "file system full") || strings.Contains(msg, "no space")
+}
@@ -0,0 +1,218 @@
+//go:build !plan9
+// +build !plan9
+
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+// fullDisk fails writes with ENOSPC while it is full, failing the given number of writes
+// first when fail is positive
+type fullDisk struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	full     bool
+	fail     int
+	attempts int
+}
+
+func (d *fullDisk) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.attempts++
+	if d.full || d.fail > 0 {
+		d.fail--
+		return 0, &os.PathError{Op: "write", Path: "app.log", Err: syscall.ENOSPC}
+	}
+
+	return d.buf.Write(p)
+}
+
+func (d *fullDisk) setFull(full bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.full = full
+}
+
+// captureInternalLog collects diagnostics of the package until the test finishes
+func captureInternalLog(t *testing.T) *Log {
+	t.Helper()
+
+	il := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Verbose})
+	internalMu.Lock()
+	prev := internalLog
+	internalLog = il
+	internalMu.Unlock()
+	t.Cleanup(func() { SetInternalLog(prev) })
+
+	return il
+}
+
+func countLines(lines []string, s string) int {
+	n := 0
+	for _, line := range lines {
+		if strings.Contains(line, s) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestDiskFullDrop(t *testing.T) {
+	il := captureInternalLog(t)
+	l := &Log{}
+	disk := &fullDisk{full: true}
+	w := l.diskFull(disk, LoggerConfig{Path: "app.log"})
+
+	for i := 0; i < 3; i++ {
+		if n, err := w.Write([]byte("dropped\n")); err != nil || n != 8 {
+			t.Errorf("write returned %d, %v", n, err)
+		}
+	}
+	disk.setFull(false)
+	w.Write([]byte("written\n"))
+	disk.setFull(true)
+	w.Write([]byte("dropped again\n"))
+
+	if got := l.Stats().DroppedDiskFull; got != 4 {
+		t.Errorf("dropped %d messages, want 4", got)
+	}
+	if got := disk.buf.String(); got != "written\n" {
+		t.Errorf("disk got %q", got)
+	}
+	lines := exportLines(il)
+	if n := countLines(lines, "is full, dropping messages"); n != 2 {
+		t.Errorf("got %d disk full warnings, want one per incident: %q", n, lines)
+	}
+	if n := countLines(lines, "is writable again"); n != 1 {
+		t.Errorf("got %d recovery messages, want 1: %q", n, lines)
+	}
+}
+
+func TestDiskFullFallback(t *testing.T) {
+	silenceInternalLog(t)
+	_, errPath := redirectStd(t)
+	l := &Log{}
+	w := l.diskFull(&fullDisk{full: true}, LoggerConfig{Path: "app.log", DiskFullAction: "Fallback"})
+
+	if _, err := w.Write([]byte("rescued\n")); err != nil {
+		t.Fatal(err)
+	}
+	if lines := readLines(t, errPath); len(lines) != 1 || lines[0] != "rescued" {
+		t.Errorf("standard error got %q", lines)
+	}
+	if got := l.Stats().DroppedDiskFull; got != 0 {
+		t.Errorf("dropped %d messages", got)
+	}
+}
+
+func TestDiskFullRetry(t *testing.T) {
+	silenceInternalLog(t)
+
+	for _, tc := range []struct {
+		name     string
+		disk     *fullDisk
+		attempts int
+		dropped  uint64
+	}{
+		{"space freed", &fullDisk{fail: 2}, 3, 0},
+		{"still full", &fullDisk{full: true}, 1 + diskFullRetries, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &Log{}
+			w := l.diskFull(tc.disk, LoggerConfig{Path: "app.log", DiskFullAction: DiskFullRetry, DiskFullPause: "1ms"})
+			if _, err := w.Write([]byte("message\n")); err != nil {
+				t.Fatal(err)
+			}
+			if tc.disk.attempts != tc.attempts {
+				t.Errorf("made %d attempts, want %d", tc.disk.attempts, tc.attempts)
+			}
+			if got := l.Stats().DroppedDiskFull; got != tc.dropped {
+				t.Errorf("dropped %d messages, want %d", got, tc.dropped)
+			}
+		})
+	}
+}
+
+func TestDiskFullRetryOncePerIncident(t *testing.T) {
+	silenceInternalLog(t)
+	l := &Log{}
+	disk := &fullDisk{full: true}
+	w := l.diskFull(disk, LoggerConfig{Path: "app.log", DiskFullAction: DiskFullRetry, DiskFullPause: "1ms"})
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("message\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := 1 + diskFullRetries + 2; disk.attempts != want {
+		t.Errorf("made %d attempts while full, want %d", disk.attempts, want)
+	}
+
+	// the next incident retries again
+	disk.setFull(false)
+	w.Write([]byte("written\n"))
+	disk.setFull(true)
+	disk.attempts = 0
+	w.Write([]byte("message\n"))
+	if want := 1 + diskFullRetries; disk.attempts != want {
+		t.Errorf("made %d attempts in the next incident, want %d", disk.attempts, want)
+	}
+	if got := l.Stats().DroppedDiskFull; got != 4 {
+		t.Errorf("dropped %d messages, want 4", got)
+	}
+}
+
+func TestDiskFullOtherErrors(t *testing.T) {
+	l := &Log{}
+	failure := errors.New("device removed")
+	w := l.diskFull(writerFunc(func(p []byte) (int, error) { return 0, failure }), LoggerConfig{Path: "app.log"})
+
+	if _, err := w.Write([]byte("message\n")); err != failure {
+		t.Errorf("got error %v, want %v", err, failure)
+	}
+	if got := l.Stats().DroppedDiskFull; got != 0 {
+		t.Errorf("dropped %d messages", got)
+	}
+}
+
+// TestDiskFullDevice writes concurrently into /dev/full, which fails every write with ENOSPC
+func TestDiskFullDevice(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full is not available")
+	}
+	silenceInternalLog(t)
+	l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: "/dev/full"})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				l.Info("message")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := l.Stats().DroppedDiskFull; got != 100 {
+		t.Errorf("dropped %d messages, want 100", got)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const textTimeLayout = "2006/01/02 15:04:05.000000"
+
+// dualWriter writes text form of messages into standard error in addition to
+// JSON form written into the file of dual logger
+type dualWriter struct {
+	mu   sync.Mutex
+	text io.Writer
+}
+
+// writeDual renders both forms of the message first and writes the text form only after
+// the JSON form was written successfully, so a failed file write is not visible on screen.
+// Text form writes text instead of msg. It returns number of bytes written in both forms.
+func (l *Logger) writeDual(t time.Time, severity LogSeverity, msg, text string,
+	fields map[string]interface{}, fieldsText string) (int, error) {
+	jsonLine := l.encodeJSON(t, severity, msg, fields) + "\n"
+	textLine := fmt.Sprintf("%s%s %s%s%s%s\n", l.prefix, t.Format(textTimeLayout), l.severityText(severity),
+		l.separator, text, fieldsText)
+
+	l.dual.mu.Lock()
+	defer l.dual.mu.Unlock()
+
+	n, err := io.WriteString(l.rawLogger.Writer(), jsonLine)
+	if err != nil {
+		return n, err
+	}
+
+	m, err := io.WriteString(l.dual.text, textLine)
+
+	return n + m, err
+}
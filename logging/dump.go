@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigRedactor returns value of the configuration setting as written by DumpConfig,
+// e.g. to hide network addresses
+type ConfigRedactor func(key, value string) string
+
+// SetConfigRedactor sets function applied to settings written by DumpConfig, nil redacts nothing
+func (l *Log) SetConfigRedactor(fn ConfigRedactor) {
+	l = l.base()
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.redactor = fn
+}
+
+// DumpConfig returns effective configuration of the loggers, one line per logger with its
+// type, current severity, target, format and rotation settings, suitable to be logged at startup
+func (l *Log) DumpConfig() string {
+	l = l.base()
+	if l == nil {
+		return ""
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var sb strings.Builder
+	for i, lg := range l.loggers {
+		fmt.Fprintf(&sb, "logger %d:", i)
+		l.dumpSetting(&sb, "type", strings.ToLower(lg.config.LogType))
+		l.dumpSetting(&sb, "severity", getSeverityName(lg.severity))
+
+		switch lg.logType {
+		case Network:
+			l.dumpSetting(&sb, "protocol", lg.config.Protocol)
+			l.dumpSetting(&sb, "address", lg.config.Address)
+		case Sharded:
+			l.dumpSetting(&sb, "paths", strings.Join(lg.config.Paths, ","))
+			l.dumpSetting(&sb, "shardBy", lg.config.ShardBy)
+		case File, Dual:
+			l.dumpSetting(&sb, "path", lg.config.Path)
+		}
+
+		format := lg.format
+		if lg.logType == Dual {
+			format = "dual"
+		} else if format == "" {
+			format = FormatText
+		}
+		l.dumpSetting(&sb, "format", format)
+
+		if lg.logType == File || lg.logType == Dual || lg.logType == Sharded {
+			l.dumpSetting(&sb, "rotate", fmt.Sprintf("%t", lg.config.Rotate))
+		}
+		if lg.config.Async {
+			l.dumpSetting(&sb, "async", fmt.Sprintf("%t", lg.config.Async))
+		}
+		if lg.config.Lazy {
+			l.dumpSetting(&sb, "lazy", fmt.Sprintf("%t", lg.config.Lazy))
+		}
+		if lg.dryRun {
+			l.dumpSetting(&sb, "dryRun", fmt.Sprintf("%t", lg.dryRun))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// dumpSetting writes key=value pair passed through the redactor, must be called with l.mu locked
+func (l *Log) dumpSetting(sb *strings.Builder, key, value string) {
+	if l.redactor != nil {
+		value = l.redactor(key, value)
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(key)
+	sb.WriteString("=")
+	sb.WriteString(formatFieldValue(value))
+}
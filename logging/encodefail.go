@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Handling of field values failing to encode as JSON
+const (
+	// EncodeErrorsFallback writes the value rendered by %v instead
+	EncodeErrorsFallback = "fallback"
+	// EncodeErrorsDrop leaves the field out
+	EncodeErrorsDrop = "drop"
+	// EncodeErrorsField writes the value rendered by %v and adds _encode_error field
+	// describing the failures
+	EncodeErrorsField = "field"
+)
+
+// encodeErrorKey is the field describing fields which failed to encode
+const encodeErrorKey = "_encode_error"
+
+// reservedFieldPrefix is prepended to keys of fields colliding with standard keys of JSON entry
+const reservedFieldPrefix = "fields."
+
+func validEncodeErrors(policy string) bool {
+	switch policy {
+	case "", EncodeErrorsFallback, EncodeErrorsDrop, EncodeErrorsField:
+		return true
+	}
+
+	return false
+}
+
+// marshalValue encodes value as JSON, panic of its MarshalJSON is returned as error
+func marshalValue(value interface{}) (b []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return json.Marshal(value)
+}
+
+// writeJSONFields writes fields sorted by key, each preceded by comma, values failing
+// to encode are handled according to the logger's policy and counted in Stats. Keys in
+// reserved (standard keys of the entry, and _encode_error with field policy) are written
+// prefixed by "fields.", repeatedly while the key is taken by another field, so every key
+// is written once.
+func (l *Logger) writeJSONFields(sb *strings.Builder, fields map[string]interface{}, reserved ...string) {
+	if l.encodeErrors == EncodeErrorsField {
+		reserved = append(reserved, encodeErrorKey)
+	}
+	isReserved := func(k string) bool {
+		for _, r := range reserved {
+			if k == r {
+				return true
+			}
+		}
+		return false
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	written := make(map[string]bool, len(keys))
+	taken := func(k string) bool {
+		_, ok := fields[k]
+		return ok || written[k]
+	}
+	var failures []string
+	for _, k := range keys {
+		value := normalizeValue(fields[k], 0)
+		b, err := marshalValue(value)
+		if err != nil {
+			if l.stats != nil {
+				atomic.AddUint64(&l.stats.encodeErrors, 1)
+			}
+			if l.encodeErrors == EncodeErrorsDrop {
+				continue
+			}
+			if l.encodeErrors == EncodeErrorsField {
+				failures = append(failures, k+": "+err.Error())
+			}
+			b, _ = json.Marshal(fmt.Sprintf("%v", value))
+		}
+
+		key := k
+		for isReserved(key) || key != k && taken(key) {
+			key = reservedFieldPrefix + key
+		}
+		written[key] = true
+
+		sb.WriteString(",")
+		writeJSONValue(sb, key)
+		sb.WriteString(":")
+		sb.Write(b)
+	}
+
+	if len(failures) > 0 {
+		writeJSONField(sb, encodeErrorKey, strings.Join(failures, "; "))
+	}
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// badMarshaler fails to encode as JSON
+type badMarshaler struct{}
+
+func (badMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("unsupported")
+}
+
+func (badMarshaler) String() string {
+	return "bad"
+}
+
+// panicMarshaler panics when encoded as JSON
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) {
+	panic("boom")
+}
+
+func TestEncodeErrors(t *testing.T) {
+	fields := map[string]interface{}{"bad": badMarshaler{}, "panic": panicMarshaler{}, "ok": 1}
+
+	for _, tc := range []struct {
+		policy  string
+		want    map[string]interface{}
+		errKeys []string
+	}{
+		{"", map[string]interface{}{"bad": "bad", "panic": "{}", "ok": float64(1)}, nil},
+		{EncodeErrorsFallback, map[string]interface{}{"bad": "bad", "panic": "{}", "ok": float64(1)}, nil},
+		{EncodeErrorsDrop, map[string]interface{}{"ok": float64(1)}, nil},
+		{EncodeErrorsField, map[string]interface{}{"bad": "bad", "panic": "{}", "ok": float64(1)}, []string{"bad: ", "panic: "}},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatJSON, EncodeErrors: tc.policy})
+			l.WithFields(fields).Info("message")
+
+			lines := exportLines(l)
+			if len(lines) != 1 {
+				t.Fatalf("got %d lines, want 1", len(lines))
+			}
+			var m map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+				t.Fatalf("invalid JSON %s: %s", lines[0], err)
+			}
+
+			got := map[string]interface{}{}
+			for k := range fields {
+				if v, ok := m[k]; ok {
+					got[k] = v
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got fields %v, want %v", got, tc.want)
+			}
+
+			encodeErr, ok := m[encodeErrorKey].(string)
+			if ok != (tc.errKeys != nil) {
+				t.Errorf("got %s field %q", encodeErrorKey, encodeErr)
+			}
+			for _, k := range tc.errKeys {
+				if !strings.Contains(encodeErr, k) {
+					t.Errorf("%s field %q does not describe %s", encodeErrorKey, encodeErr, k)
+				}
+			}
+			if got := l.Stats().EncodeErrors; got != 2 {
+				t.Errorf("counted %d encode errors, want 2", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package logging
+
+import "fmt"
+
+// EncoderConfig is named encoding configuration shared by loggers referencing it by Encoder
+type EncoderConfig struct {
+	Format           string    `json:"format" yaml:"format"`
+	SeverityEncoding string    `json:"severityEncoding" yaml:"severityEncoding"`
+	SeverityStyle    string    `json:"severityStyle" yaml:"severityStyle"`
+	FieldKeys        FieldKeys `json:"fieldKeys" yaml:"fieldKeys"`
+}
+
+// applyEncoder fills encoding settings of the logger from the encoder it references,
+// settings given by the logger itself take precedence
+func applyEncoder(item LoggerConfig, encoders map[string]EncoderConfig) (LoggerConfig, error) {
+	if item.Encoder == "" {
+		return item, nil
+	}
+
+	enc, ok := encoders[item.Encoder]
+	if !ok {
+		return item, fmt.Errorf("encoder %s does not exist", item.Encoder)
+	}
+
+	if item.Format == "" {
+		item.Format = enc.Format
+	}
+	if item.SeverityEncoding == "" {
+		item.SeverityEncoding = enc.SeverityEncoding
+	}
+	if item.SeverityStyle == "" {
+		item.SeverityStyle = enc.SeverityStyle
+	}
+	if item.FieldKeys.Time == "" {
+		item.FieldKeys.Time = enc.FieldKeys.Time
+	}
+	if item.FieldKeys.Level == "" {
+		item.FieldKeys.Level = enc.FieldKeys.Level
+	}
+	if item.FieldKeys.Message == "" {
+		item.FieldKeys.Message = enc.FieldKeys.Message
+	}
+
+	return item, nil
+}
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	errorExtractorsMu sync.RWMutex
+	errorExtractors   []func(err error) (map[string]interface{}, bool)
+)
+
+// RegisterErrorExtractor registers function extracting fields from typed errors (e.g. op
+// and net of *net.OpError) logged by Errore. The function is called with the logged error
+// and then with each error it wraps (see errors.Unwrap) until it returns true, so it only
+// has to check the error's type. Fields of later registered extractors override the earlier.
+func RegisterErrorExtractor(fn func(err error) (map[string]interface{}, bool)) {
+	if fn == nil {
+		return
+	}
+
+	errorExtractorsMu.Lock()
+	defer errorExtractorsMu.Unlock()
+
+	errorExtractors = append(errorExtractors, fn)
+}
+
+// errorFields returns fields extracted from the error chain by registered extractors
+func errorFields(err error) map[string]interface{} {
+	errorExtractorsMu.RLock()
+	extractors := errorExtractors
+	errorExtractorsMu.RUnlock()
+
+	var fields map[string]interface{}
+	for _, extract := range extractors {
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			if f, ok := extract(e); ok {
+				fields = mergeFields(fields, f)
+				break
+			}
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// domainError is typed error carrying fields worth logging
+type domainError struct {
+	op   string
+	code int
+}
+
+func (e *domainError) Error() string {
+	return fmt.Sprintf("%s failed with code %d", e.op, e.code)
+}
+
+// registerErrorExtractor registers extractor until the test finishes
+func registerErrorExtractor(t *testing.T, fn func(err error) (map[string]interface{}, bool)) {
+	errorExtractorsMu.RLock()
+	prev := errorExtractors
+	errorExtractorsMu.RUnlock()
+
+	RegisterErrorExtractor(fn)
+	t.Cleanup(func() {
+		errorExtractorsMu.Lock()
+		errorExtractors = prev
+		errorExtractorsMu.Unlock()
+	})
+}
+
+func TestRegisterErrorExtractor(t *testing.T) {
+	registerErrorExtractor(t, func(err error) (map[string]interface{}, bool) {
+		if e, ok := err.(*domainError); ok {
+			return map[string]interface{}{"op": e.op, "code": e.code}, true
+		}
+		return nil, false
+	})
+	registerErrorExtractor(t, func(err error) (map[string]interface{}, bool) {
+		if e, ok := err.(*domainError); ok && e.code >= 500 {
+			return map[string]interface{}{"code": "server"}, true
+		}
+		return nil, false
+	})
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want map[string]interface{}
+	}{
+		{"typed", &domainError{"charge", 402}, map[string]interface{}{"op": "charge", "code": float64(402)}},
+		{"wrapped", fmt.Errorf("checkout: %w", &domainError{"charge", 402}), map[string]interface{}{"op": "charge", "code": float64(402)}},
+		{"overridden", &domainError{"refund", 503}, map[string]interface{}{"op": "refund", "code": "server"}},
+		{"plain", errors.New("timeout"), map[string]interface{}{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatJSON})
+			l.Errore(tc.err)
+
+			lines := exportLines(l)
+			if len(lines) != 1 {
+				t.Fatalf("got %d lines, want 1", len(lines))
+			}
+			var m map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+				t.Fatal(err)
+			}
+			if m["msg"] != tc.err.Error() {
+				t.Errorf("got message %v, want %s", m["msg"], tc.err.Error())
+			}
+			for _, k := range []string{"time", "level", "msg"} {
+				delete(m, k)
+			}
+			if !reflect.DeepEqual(m, tc.want) {
+				t.Errorf("got fields %v, want %v", m, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegisterErrorExtractorNil(t *testing.T) {
+	registerErrorExtractor(t, nil)
+
+	if fields := errorFields(errors.New("plain")); fields != nil {
+		t.Errorf("got fields %v", fields)
+	}
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxRepeatKeys limits number of distinct messages tracked by each escalation rule
+const maxRepeatKeys = 1000
+
+// repeat counts occurrences of a message within the current window
+type repeat struct {
+	start time.Time
+	count int
+}
+
+// escalation raises severity of a message repeated more than threshold times within window
+type escalation struct {
+	from      LogSeverity
+	to        LogSeverity
+	threshold int
+	window    time.Duration
+
+	mu   sync.Mutex
+	seen map[uint64]*repeat
+}
+
+// EscalateRepeated makes message of severity from written with severity to once it repeats more
+// than threshold times within window, e.g. to turn persistent warning into an error firing alerts.
+// Messages are told apart by fingerprint of their text (the format string for the formatted
+// methods, so messages differing only in arguments count together). At most 1000 distinct
+// messages are tracked per rule.
+func (l *Log) EscalateRepeated(from LogSeverity, threshold int, window time.Duration, to LogSeverity) error {
+	if !validSeverity(from) {
+		return fmt.Errorf("%d is invalid severity", from)
+	}
+	if !validSeverity(to) {
+		return fmt.Errorf("%d is invalid severity", to)
+	}
+	if threshold <= 0 || window <= 0 {
+		return fmt.Errorf("escalation threshold and window must be positive")
+	}
+
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.escalations = append(l.escalations, &escalation{
+		from:      from,
+		to:        to,
+		threshold: threshold,
+		window:    window,
+		seen:      make(map[uint64]*repeat),
+	})
+
+	return nil
+}
+
+// clone copies the rule without its repeat counts
+func (e *escalation) clone() *escalation {
+	return &escalation{
+		from:      e.from,
+		to:        e.to,
+		threshold: e.threshold,
+		window:    e.window,
+		seen:      make(map[uint64]*repeat),
+	}
+}
+
+func fingerprint(msg string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(msg))
+
+	return h.Sum64()
+}
+
+// escalate returns severity the message is written with after applying the escalation rules
+func (l *Log) escalate(severity LogSeverity, msg string) LogSeverity {
+	l.mu.RLock()
+	escalations := l.escalations
+	l.mu.RUnlock()
+
+	for _, e := range escalations {
+		if e.from == severity && e.record(fingerprint(msg), time.Now()) {
+			atomic.AddUint64(&l.counters.escalated, 1)
+			return e.to
+		}
+	}
+
+	return severity
+}
+
+// record counts occurrence of the message and reports whether it exceeded the threshold
+func (e *escalation) record(fp uint64, now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	r, ok := e.seen[fp]
+	if !ok {
+		if len(e.seen) >= maxRepeatKeys {
+			for k, v := range e.seen {
+				if now.Sub(v.start) > e.window {
+					delete(e.seen, k)
+				}
+			}
+			if len(e.seen) >= maxRepeatKeys {
+				return false
+			}
+		}
+		r = &repeat{start: now}
+		e.seen[fp] = r
+	} else if now.Sub(r.start) > e.window {
+		r.start, r.count = now, 0
+	}
+
+	r.count++
+
+	return r.count > e.threshold
+}
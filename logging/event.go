@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is structured log message as written into the loggers and passed to handlers
+// registered by OnEvent. Fields include fields of the log the message was written by,
+// they must not be modified.
+type Event struct {
+	Severity LogSeverity
+	Message  string
+	Fields   map[string]interface{}
+	Time     time.Time
+}
+
+// Emit writes event into the log, its fields are added to fields of the log and zero
+// time is replaced by the current time. Invalid severity is rejected.
+func (l *Log) Emit(e Event) error {
+	if !validSeverity(e.Severity) {
+		return fmt.Errorf("%d is invalid severity", e.Severity)
+	}
+
+	b := l.base()
+	if b == nil {
+		return nil
+	}
+
+	e.Severity = b.escalate(b.remap(e.Severity, func() string { return e.Message }), e.Message)
+	if !b.enabledLocked(e.Severity, l.name) || !b.accept(e.Severity, e.Message) {
+		return nil
+	}
+
+	b.mu.RLock()
+	if e.Time.IsZero() {
+		e.Time = b.now()
+	}
+	fields, fieldsText := l.entryFields()
+	if len(e.Fields) > 0 {
+		fields = mergeFields(fields, e.Fields)
+		fieldsText = formatFields(fields)
+	}
+	e.Fields = fields
+	b.emit(&e, fieldsText, l)
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	publish(handlers, e)
+
+	return nil
+}
+
+// OnEvent registers handler receiving every message written into the loggers as
+// structured event. Handlers are called in the writing goroutine after the message
+// has been written. Handlers may log, messages they write are passed to the handlers too.
+func (l *Log) OnEvent(handler func(Event)) {
+	l = l.base()
+	if l == nil || handler == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.handlers = append(l.handlers, handler)
+}
+
+// publish passes event to the handlers, it must be called without the log locked
+func publish(handlers []func(Event), e Event) {
+	for _, h := range handlers {
+		h(e)
+	}
+}
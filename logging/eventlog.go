@@ -0,0 +1,85 @@
+//go:build windows
+// +build windows
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventID is the identifier of all events written into the Windows Event Log
+const eventID = 1
+
+func init() {
+	registerJSONTarget("eventlog", newEventLogWriter)
+}
+
+// eventLogWriter writes messages into the Windows Event Log as error (Fatal, Error), warning
+// (Warning) or information (others) events of source AppName (program name by default).
+// Event text is the message followed by its fields. Messages are passed to the writer in
+// JSON format, which is the default format of eventlog loggers, and other formats are rejected.
+type eventLogWriter struct {
+	log  *eventlog.Log
+	keys FieldKeys
+}
+
+func newEventLogWriter(cfg LoggerConfig) (io.WriteCloser, error) {
+	if strings.ToLower(cfg.Format) != FormatJSON {
+		return nil, fmt.Errorf("eventlog logger supports only json format, %s is set", cfg.Format)
+	}
+
+	source := cfg.AppName
+	if source == "" {
+		source = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	}
+
+	keys, err := resolveFieldKeys(cfg.FieldKeys, strings.ToLower(cfg.SeverityEncoding), cfg.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// registration fails when the source already exists or without administrator rights,
+	// opening the log reveals whether the source is usable
+	eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+
+	el, err := eventlog.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log source %s: %s", source, err.Error())
+	}
+
+	return &eventLogWriter{log: el, keys: keys}, nil
+}
+
+// Write implements io.Writer interface
+func (w *eventLogWriter) Write(p []byte) (int, error) {
+	e, err := decodeJSON(p, w.keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode log message: %s", err.Error())
+	}
+
+	text := e.message + formatFields(e.fields)
+	switch e.severity {
+	case Fatal, Error:
+		err = w.log.Error(eventID, text)
+	case Warning:
+		err = w.log.Warning(eventID, text)
+	default:
+		err = w.log.Info(eventID, text)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close implements io.Closer interface
+func (w *eventLogWriter) Close() error {
+	return w.log.Close()
+}
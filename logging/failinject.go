@@ -0,0 +1,59 @@
+//go:build faultinject
+// +build faultinject
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+// failWriter fails all writes instead of writing into out
+type failWriter struct {
+	out   io.Writer
+	index int
+}
+
+// Write implements io.Writer interface
+func (w *failWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("write into logger %d failed by SetFailTarget", w.index)
+}
+
+// SetFailTarget makes writes of logger with the given index fail (or succeed again), including
+// writes of console logger into standard error and of all shards, so handling of failing targets can be tested without breaking a real target.
+// It is a testing aid available only when built with faultinject tag.
+func (l *Log) SetFailTarget(index int, fail bool) error {
+	l = l.base()
+	if l == nil {
+		return fmt.Errorf("logger %d does not exist", index)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if index < 0 || index >= len(l.loggers) {
+		return fmt.Errorf("logger %d does not exist", index)
+	}
+
+	lg := l.loggers[index]
+	setFail(lg.rawLogger, index, fail)
+	if lg.errLogger != nil {
+		setFail(lg.errLogger, index, fail)
+	}
+	for _, rl := range lg.shards {
+		setFail(rl, index, fail)
+	}
+
+	return nil
+}
+
+func setFail(rl *log.Logger, index int, fail bool) {
+	fw, failing := rl.Writer().(*failWriter)
+	switch {
+	case fail && !failing:
+		rl.SetOutput(&failWriter{out: rl.Writer(), index: index})
+	case !fail && failing:
+		rl.SetOutput(fw.out)
+	}
+}
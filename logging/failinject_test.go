@@ -0,0 +1,50 @@
+//go:build faultinject
+// +build faultinject
+
+package logging
+
+import "testing"
+
+func TestSetFailTarget(t *testing.T) {
+	redirectStd(t)
+	silenceInternalLog(t)
+	dir := tempDir(t)
+
+	for _, tc := range []struct {
+		name     string
+		item     LoggerConfig
+		severity LogSeverity
+	}{
+		{"file", LoggerConfig{LogType: "file", Path: dir + "/app.log"}, Information},
+		{"console stdout", LoggerConfig{LogType: "console"}, Information},
+		{"console stderr", LoggerConfig{LogType: "console"}, Error},
+		{"sharded", LoggerConfig{LogType: "sharded", Paths: []string{dir + "/a.log", dir + "/b.log"}}, Information},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			item := tc.item
+			item.Severity = Information
+			l := newTestLog(t, item)
+
+			if err := l.SetFailTarget(0, true); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := l.LogBytes(tc.severity, "failing"); err == nil {
+					t.Error("write into failing target succeeded")
+				}
+			}
+
+			if err := l.SetFailTarget(0, false); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := l.LogBytes(tc.severity, "restored"); err != nil {
+				t.Errorf("write into restored target failed: %v", err)
+			}
+		})
+	}
+
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+	if err := l.SetFailTarget(1, true); err == nil {
+		t.Error("missing logger was made failing")
+	}
+}
@@ -0,0 +1,429 @@
+package logging
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// mergeFields returns new map containing fields of base overridden by fields of other
+func mergeFields(base, other map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(other))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// fieldsTruncatedKey is the field with number of fields dropped by MaxFields
+const fieldsTruncatedKey = "fields_truncated"
+
+// limitFields returns at most max fields, the most recently added ones, followed by
+// fields_truncated field with number of the dropped ones. Order lists keys from the least
+// recently added one, keys missing in it (fields of the call and fields added by the log)
+// are the most recent, fields added at once are ordered by key.
+func limitFields(fields map[string]interface{}, order []string, max int) map[string]interface{} {
+	if len(fields) <= max {
+		return fields
+	}
+
+	rank := make(map[string]int, len(order))
+	for i, k := range order {
+		rank[k] = i + 1
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, oki := rank[keys[i]]
+		rj, okj := rank[keys[j]]
+		if oki != okj {
+			return !oki
+		}
+		if ri != rj {
+			return ri > rj
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	limited := make(map[string]interface{}, max+1)
+	for _, k := range keys[:max] {
+		limited[k] = fields[k]
+	}
+	limited[fieldsTruncatedKey] = len(keys) - max
+
+	return limited
+}
+
+// appendOrder returns order of field keys with keys of fields moved or added to its end
+// ordered by key, order itself is not modified
+func appendOrder(order []string, fields map[string]interface{}) []string {
+	added := make([]string, 0, len(fields))
+	for k := range fields {
+		added = append(added, k)
+	}
+	sort.Strings(added)
+
+	appended := make([]string, 0, len(order)+len(added))
+	for _, k := range order {
+		if _, ok := fields[k]; !ok {
+			appended = append(appended, k)
+		}
+	}
+
+	return append(appended, added...)
+}
+
+// fieldOrder returns keys of default fields and fields of the log from the least recently
+// added one, must be called with root's mu locked for reading
+func (l *Log) fieldOrder() []string {
+	if l.root == nil {
+		return l.order
+	}
+
+	return append(append([]string(nil), l.root.order...), l.order...)
+}
+
+// truncatedMarker is appended to field values cut by MaxFieldValueBytes
+const truncatedMarker = "..."
+
+// truncateFields returns fields with string values (including nested ones) longer than max
+// bytes cut at rune boundary and followed by truncatedMarker, it reports whether any value
+// was cut. Fields themselves are returned when no value is cut.
+func truncateFields(fields map[string]interface{}, max int) (map[string]interface{}, bool) {
+	var truncated map[string]interface{}
+	for k, v := range fields {
+		if tv, ok := truncateValue(normalizeValue(v, 0), max); ok {
+			if truncated == nil {
+				truncated = mergeFields(fields, nil)
+			}
+			truncated[k] = tv
+		}
+	}
+
+	if truncated == nil {
+		return fields, false
+	}
+
+	return truncated, true
+}
+
+// truncateValue cuts strings of normalized value, it reports whether any was cut
+func truncateValue(value interface{}, max int) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if len(v) <= max {
+			return v, false
+		}
+
+		n := max
+		for n > 0 && !utf8.RuneStart(v[n]) {
+			n--
+		}
+
+		return v[:n] + truncatedMarker, true
+	case map[string]interface{}:
+		return truncateFields(v, max)
+	case []interface{}:
+		var items []interface{}
+		for i, item := range v {
+			if tv, ok := truncateValue(item, max); ok {
+				if items == nil {
+					items = append([]interface{}(nil), v...)
+				}
+				items[i] = tv
+			}
+		}
+		if items == nil {
+			return v, false
+		}
+
+		return items, true
+	}
+
+	return value, false
+}
+
+// maxFieldDepth limits nesting of structs, maps and slices rendered from field values,
+// deeper values are rendered using %v
+const maxFieldDepth = 5
+
+// maxFieldItems limits number of rendered map entries and slice elements of field values
+const maxFieldItems = 100
+
+// maskedValue replaces values of struct members tagged log:"mask"
+const maskedValue = "***"
+
+// callString returns result of Error or String method of value, panic of the method is rendered
+// as fmt does, e.g. method with value receiver called on nil pointer gives <nil>
+func callString(value interface{}, method func() string) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				s = "<nil>"
+				return
+			}
+			s = "%!v(PANIC=" + fmt.Sprint(r) + ")"
+		}
+	}()
+
+	return method()
+}
+
+// normalizeValue converts field value into a value consisting of scalars,
+// map[string]interface{} and []interface{} only, so it can be encoded by any format.
+// Members of structs tagged log:"-" are omitted and those tagged log:"mask" are written
+// as *** (e.g. Password string `log:"mask"`).
+func normalizeValue(value interface{}, depth int) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return v
+	case []byte:
+		return renderBytes(v)
+	case error:
+		return callString(value, v.Error)
+	case json.Marshaler, encoding.TextMarshaler:
+		return v
+	case fmt.Stringer:
+		return callString(value, v.String)
+	}
+
+	if depth >= maxFieldDepth {
+		return fmt.Sprintf("%v", value)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+
+		return normalizeValue(rv.Elem().Interface(), depth+1)
+	case reflect.Struct:
+		m := make(map[string]interface{}, rv.NumField())
+		t := rv.Type()
+		for i := 0; i < rv.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			switch f.Tag.Get("log") {
+			case "-":
+				continue
+			case "mask":
+				m[f.Name] = maskedValue
+				continue
+			}
+			m[f.Name] = normalizeValue(rv.Field(i).Interface(), depth+1)
+		}
+
+		return m
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+
+		keys := make([]string, 0, rv.Len())
+		values := make(map[string]reflect.Value, rv.Len())
+		for _, k := range rv.MapKeys() {
+			key := fmt.Sprintf("%v", k.Interface())
+			keys = append(keys, key)
+			values[key] = rv.MapIndex(k)
+		}
+		sort.Strings(keys)
+
+		m := make(map[string]interface{}, len(keys))
+		for i, k := range keys {
+			if i == maxFieldItems {
+				m["..."] = fmt.Sprintf("%d more", len(keys)-maxFieldItems)
+				break
+			}
+			m[k] = normalizeValue(values[k].Interface(), depth+1)
+		}
+
+		return m
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+
+		items := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if i == maxFieldItems {
+				items = append(items, fmt.Sprintf("... %d more", rv.Len()-maxFieldItems))
+				break
+			}
+			items = append(items, normalizeValue(rv.Index(i).Interface(), depth+1))
+		}
+
+		return items
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return value
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// formatFields renders fields as key=value pairs sorted by key, each preceded by space.
+// Structs, maps and slices are flattened using dotted keys (e.g. user.Name=john).
+func formatFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeTextFields(&sb, "", fields)
+
+	return sb.String()
+}
+
+func writeTextFields(sb *strings.Builder, prefix string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		writeTextField(sb, prefix+k, normalizeValue(fields[k], 0))
+	}
+}
+
+func writeTextField(sb *strings.Builder, key string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			break
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			writeTextField(sb, key+"."+k, v[k])
+		}
+		return
+	case []interface{}:
+		if len(v) == 0 {
+			break
+		}
+
+		for i, item := range v {
+			writeTextField(sb, key+"."+strconv.Itoa(i), item)
+		}
+		return
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(key)
+	sb.WriteString("=")
+	sb.WriteString(formatFieldValue(value))
+}
+
+func formatFieldValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s = "{}"
+	case []interface{}:
+		s = "[]"
+	case nil:
+		s = "<nil>"
+	default:
+		s = fmt.Sprintf("%v", v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// setFields replaces fields of the log, keys of removed fields are dropped from their order,
+// must be called with l.mu locked
+func (l *Log) setFields(fields map[string]interface{}) {
+	l.fields = fields
+	l.fieldsText = formatFields(fields)
+
+	order := l.order[:0:0]
+	for _, k := range l.order {
+		if _, ok := fields[k]; ok {
+			order = append(order, k)
+		}
+	}
+	l.order = order
+}
+
+// base returns the root log owning loggers and settings
+func (l *Log) base() *Log {
+	if l == nil {
+		return nil
+	}
+
+	if l.root != nil {
+		return l.root
+	}
+
+	return l
+}
+
+// entryFields returns fields of the log merged with default fields of the root,
+// must be called with root's mu locked for reading
+func (l *Log) entryFields() (map[string]interface{}, string) {
+	if l.root == nil {
+		return l.fields, l.fieldsText
+	}
+
+	if len(l.root.fields) == 0 {
+		return l.fields, l.fieldsText
+	}
+
+	fields := mergeFields(l.root.fields, l.fields)
+
+	return fields, formatFields(fields)
+}
+
+// WithFields returns log which adds the given fields to every message. Fields of the returned
+// log override default fields and fields of the log it was derived from. When the same key
+// is set more than once the last one wins, from the first: default fields, fields of the logs
+// in order of derivation, fields of the call (key/value arguments, later pair winning, or
+// fields of Emit) and fields added by the log (template, seq, goroutine, caller). Every key
+// is written once. Derived log writes into the same loggers, its settings (filters,
+// severities, Close, ...) are shared with the log it was derived from. Struct members
+// tagged log:"-" or log:"mask" are omitted or masked in field values.
+func (l *Log) WithFields(fields map[string]interface{}) *Log {
+	if l == nil {
+		return nil
+	}
+
+	d := &Log{root: l.base(), name: l.name, callerSkip: l.callerSkip}
+	if l.root == nil {
+		d.order = appendOrder(nil, fields)
+		d.setFields(mergeFields(nil, fields))
+	} else {
+		d.order = appendOrder(l.order, fields)
+		d.setFields(mergeFields(l.fields, fields))
+	}
+
+	return d
+}
@@ -0,0 +1,228 @@
+package logging
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLimitFields(t *testing.T) {
+	fields := map[string]interface{}{"a": 1, "b": 2, "c": 3, "d": 4}
+
+	for _, tc := range []struct {
+		name  string
+		order []string
+		max   int
+		want  map[string]interface{}
+	}{
+		{"under limit", nil, 4, fields},
+		{"newest kept", []string{"d", "c", "b", "a"}, 2,
+			map[string]interface{}{"b": 2, "a": 1, fieldsTruncatedKey: 2}},
+		{"unordered keys are newest", []string{"a", "b"}, 3,
+			map[string]interface{}{"c": 3, "d": 4, "b": 2, fieldsTruncatedKey: 1}},
+		{"same age ordered by key", nil, 1,
+			map[string]interface{}{"a": 1, fieldsTruncatedKey: 3}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := limitFields(fields, tc.order, tc.max); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMaxFieldsKeepsRecentFields(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers:       []LoggerConfig{{LogType: "membuf", Severity: Information, MaxFields: 3}},
+		DefaultFields: map[string]string{"app": "api", "env": "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	d := l.WithFields(map[string]interface{}{"request": "r1"}).WithFields(map[string]interface{}{"user": "u1"})
+	d.Infokv("exceeded", "step", 2)
+	// re-setting a key makes it the most recent
+	d.WithFields(map[string]interface{}{"request": "r2"}).Info("reset")
+
+	lines := exportLines(l)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	for i, tc := range []struct {
+		want, notWant []string
+	}{
+		{[]string{"step=2", "user=u1", "request=r1", "fields_truncated=2"}, []string{"app=", "env="}},
+		{[]string{"request=r2", "user=u1", "env=prod", "fields_truncated=1"}, []string{"app="}},
+	} {
+		for _, w := range tc.want {
+			if !strings.Contains(lines[i], w) {
+				t.Errorf("line %q does not contain %s", lines[i], w)
+			}
+		}
+		for _, w := range tc.notWant {
+			if strings.Contains(lines[i], w) {
+				t.Errorf("line %q contains %s", lines[i], w)
+			}
+		}
+	}
+}
+
+func TestTruncateValue(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value interface{}
+		max   int
+		want  interface{}
+		cut   bool
+	}{
+		{"short", "select 1", 10, "select 1", false},
+		{"exact", "select 1", 8, "select 1", false},
+		{"long", "select * from users", 6, "select...", true},
+		{"rune boundary", "héllo", 2, "h...", true},
+		{"non string", 12345678, 2, 12345678, false},
+		{"nested", map[string]interface{}{"q": "select * from users", "n": 1}, 6,
+			map[string]interface{}{"q": "select...", "n": 1}, true},
+		{"slice", []interface{}{"abcdefgh", "ab"}, 3, []interface{}{"abc...", "ab"}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, cut := truncateValue(tc.value, tc.max)
+			if cut != tc.cut || !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, %v, want %v, %v", got, cut, tc.want, tc.cut)
+			}
+		})
+	}
+}
+
+func TestMaxFieldValueBytes(t *testing.T) {
+	query := "SELECT id, name FROM users WHERE name = 'żółw' ORDER BY id"
+	msg := strings.Repeat("long message ", 10)
+
+	for _, tc := range []struct {
+		format string
+		max    int
+		want   string
+	}{
+		{FormatText, 0, `query="` + query + `"`},
+		{FormatText, 6, "query=SELECT..."},
+		{FormatJSON, 44, `"query":"SELECT id, name FROM users WHERE name = 'ż..."`},
+		{FormatJSON, 45, `"query":"SELECT id, name FROM users WHERE name = 'żó..."`},
+	} {
+		l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: tc.format, MaxFieldValueBytes: tc.max})
+		l.WithFields(map[string]interface{}{"query": query}).Info(msg)
+
+		lines := exportLines(l)
+		if len(lines) != 1 {
+			t.Fatalf("got %d lines, want 1", len(lines))
+		}
+		if !strings.Contains(lines[0], tc.want) {
+			t.Errorf("%s line with limit %d is %s, want %s", tc.format, tc.max, lines[0], tc.want)
+		}
+		if !strings.Contains(lines[0], msg) {
+			t.Errorf("%s message was truncated with limit %d", tc.format, tc.max)
+		}
+	}
+}
+
+func TestConflictingFieldsLastWins(t *testing.T) {
+	for _, format := range []string{FormatText, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			l := &Log{}
+			err := l.SetupLoggers(LogConfig{
+				Loggers:       []LoggerConfig{{LogType: "membuf", Severity: Information, Format: format}},
+				DefaultFields: map[string]string{"k": "default", "app": "api"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			parent := l.WithFields(map[string]interface{}{"k": "parent", "p": 1})
+			child := parent.WithFields(map[string]interface{}{"k": "child", "c": 2})
+			l.Info("root")
+			parent.Info("parent")
+			child.Info("child")
+			child.Infokv("call", "k", "call", "k", "repeated")
+
+			lines := exportLines(l)
+			if len(lines) != 4 {
+				t.Fatalf("got %d lines, want 4", len(lines))
+			}
+			for i, want := range []string{"default", "parent", "child", "repeated"} {
+				var key, value string
+				if format == FormatJSON {
+					key, value = `"k":`, `"k":"`+want+`"`
+				} else {
+					key, value = " k=", " k="+want
+				}
+				if n := strings.Count(lines[i], key); n != 1 {
+					t.Errorf("line %s has %d k fields", lines[i], n)
+				}
+				if !strings.Contains(lines[i], value) {
+					t.Errorf("line %s does not contain %s", lines[i], value)
+				}
+				if !strings.Contains(lines[i], "app") {
+					t.Errorf("line %s lost default field", lines[i])
+				}
+			}
+		})
+	}
+}
+
+// valueErr implements error and fmt.Stringer by value receivers, they panic on nil pointer
+type valueErr struct{ code int }
+
+func (e valueErr) Error() string  { return "code " + strconv.Itoa(e.code) }
+func (e valueErr) String() string { return "value " + strconv.Itoa(e.code) }
+
+// valueStringer implements fmt.Stringer only
+type valueStringer struct{ name string }
+
+func (s valueStringer) String() string { return s.name }
+
+// panicStringer panics in String
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("boom") }
+
+func TestNormalizeNilPointerMethods(t *testing.T) {
+	var nilErr *valueErr
+	var nilStringer *valueStringer
+	var err error = nilErr
+
+	for _, tc := range []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{"nil error pointer", nilErr, "<nil>"},
+		{"nil error in interface", err, "<nil>"},
+		{"nil stringer pointer", nilStringer, "<nil>"},
+		{"error", &valueErr{7}, "code 7"},
+		{"stringer", valueStringer{"s"}, "s"},
+		{"panicking stringer", panicStringer{}, "%!v(PANIC=boom)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := normalizeValue(tc.value, 0); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		format, want string
+	}{
+		{FormatText, "err=<nil>"},
+		{FormatJSON, `"err":"\u003cnil\u003e"`},
+	} {
+		l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: tc.format})
+		l.WithFields(map[string]interface{}{"err": nilErr, "name": nilStringer}).Info("message")
+
+		if lines := exportLines(l); len(lines) != 1 || !strings.Contains(lines[0], tc.want) {
+			t.Errorf("%s got %q, want %s", tc.format, lines, tc.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateLogDir(t *testing.T) {
+	dir := tempDir(t)
+	chdir(t, dir)
+
+	for _, tc := range []struct {
+		name, path, file string
+	}{
+		{"bare file name", "app.log", "app.log"},
+		{"relative nested path", "logs/app/app.log", "logs/app/app.log"},
+		{"absolute nested path", dir + "/abs/nested/app.log", "abs/nested/app.log"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: tc.path})
+			l.Info("message")
+
+			if lines := readLines(t, filepath.Join(dir, tc.file)); len(lines) != 1 {
+				t.Errorf("got %d lines, want 1", len(lines))
+			}
+		})
+	}
+}
+
+func TestCreateLogDirSkipsCurrentDirectory(t *testing.T) {
+	dir := tempDir(t)
+	chdir(t, dir)
+
+	l := &Log{}
+	for _, path := range []string{"", "."} {
+		if err := l.createLogDir(path); err != nil {
+			t.Errorf("createLogDir(%q) failed: %s", path, err)
+		}
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("current directory got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+import "sync/atomic"
+
+// Filter decides whether message should be written into the log, returning false drops it
+type Filter func(severity LogSeverity, msg string) bool
+
+// AddFilter registers filter applied to all messages before they are written.
+// Message is dropped if any of the registered filters returns false. Filters are called
+// without holding locks of the log, so a filter may write into the log itself (such
+// messages pass through the filters as well).
+func (l *Log) AddFilter(fn func(severity LogSeverity, msg string) bool) {
+	l = l.base()
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.filters = append(l.filters, fn)
+}
+
+// accept runs registered filters outside of the critical section
+func (l *Log) accept(severity LogSeverity, msg string) bool {
+	l.mu.RLock()
+	filters := l.filters
+	l.mu.RUnlock()
+
+	for _, fn := range filters {
+		if !fn(severity, msg) {
+			atomic.AddUint64(&l.counters.filtered, 1)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterMayLog(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+	l.AddFilter(func(severity LogSeverity, msg string) bool {
+		if strings.HasPrefix(msg, "secret") {
+			l.Warning("dropped secret message")
+			return false
+		}
+		return true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Error("secret token")
+		l.Info("public")
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("logging from filter deadlocked")
+	}
+
+	lines := exportLines(l)
+	if len(lines) != 2 || !strings.Contains(lines[0], "dropped secret message") || !strings.Contains(lines[1], "public") {
+		t.Errorf("got %q", lines)
+	}
+	if got := l.Stats().Filtered; got != 1 {
+		t.Errorf("filtered %d messages, want 1", got)
+	}
+}
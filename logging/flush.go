@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+// syncer is implemented by targets able to commit written data to stable storage
+type syncer interface {
+	Sync() error
+}
+
+// flushWriter syncs its target after every given number of writes
+type flushWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	s       syncer
+	every   int
+	pending int
+}
+
+// flushEvery wraps file target to be synced after every n messages, n of zero leaves
+// syncing to the operating system
+func flushEvery(w io.Writer, n int) io.Writer {
+	s, ok := w.(syncer)
+	if n <= 0 || !ok {
+		return w
+	}
+
+	return &flushWriter{w: w, s: s, every: n}
+}
+
+// Write implements io.Writer interface
+func (f *flushWriter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	f.pending++
+	if f.pending >= f.every {
+		f.pending = 0
+		err = f.s.Sync()
+	}
+
+	return n, err
+}
+
+// Sync implements syncer interface
+func (f *flushWriter) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pending = 0
+
+	return f.s.Sync()
+}
+
+// syncTarget syncs closer c if it is able to
+func syncTarget(c interface{}) error {
+	if s, ok := c.(syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Sync implements syncer interface, messages still queued are not waited for
+func (w *asyncWriter) Sync() error {
+	return syncTarget(w.closer)
+}
+
+// Sync implements syncer interface
+func (m multiCloser) Sync() error {
+	var errs []string
+	for _, c := range m {
+		if err := syncTarget(c); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Flush writes messages queued by async loggers and commits log files to stable storage,
+// e.g. before the files are inspected. Returned error describes all loggers failing to sync.
+func (l *Log) Flush() error {
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	if err := l.WaitIdle(context.Background()); err != nil {
+		return err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var errs []string
+	for i, lg := range l.loggers {
+		if err := syncTarget(lg.out); err != nil {
+			errs = append(errs, fmt.Sprintf("logger %d: %s", i, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to flush loggers: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// InstallFlushSignal starts goroutine calling Flush whenever the process receives the signal
+// (e.g. syscall.SIGUSR1), so buffered messages can be inspected without shutting down.
+// Installing another signal replaces the previous one, the goroutine is stopped by Close.
+func (l *Log) InstallFlushSignal(sig os.Signal) {
+	l = l.base()
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.flushSignal != nil {
+		close(l.flushSignal)
+	}
+	stop := make(chan struct{})
+	l.flushSignal = stop
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+
+	go func() {
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ch:
+				if err := l.Flush(); err != nil {
+					internalErrorf(l, "%s", err.Error())
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
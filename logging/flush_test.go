@@ -0,0 +1,163 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+// syncCounter records written data and counts syncs
+type syncCounter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	syncs int
+}
+
+func (s *syncCounter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Write(p)
+}
+
+func (s *syncCounter) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.syncs++
+
+	return nil
+}
+
+func (s *syncCounter) Close() error {
+	return nil
+}
+
+func (s *syncCounter) synced() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.syncs
+}
+
+func TestFlushEveryN(t *testing.T) {
+	for _, tc := range []struct {
+		every, writes, syncs int
+	}{
+		{1, 3, 3},
+		{3, 2, 0},
+		{3, 3, 1},
+		{3, 7, 2},
+	} {
+		s := &syncCounter{}
+		w := flushEvery(s, tc.every)
+		for i := 0; i < tc.writes; i++ {
+			w.Write([]byte("message\n"))
+		}
+		if got := s.synced(); got != tc.syncs {
+			t.Errorf("every %d messages synced %d times after %d writes, want %d", tc.every, got, tc.writes, tc.syncs)
+		}
+	}
+}
+
+func TestFlushEveryNRestartsAfterSync(t *testing.T) {
+	s := &syncCounter{}
+	w := flushEvery(s, 3)
+	w.Write([]byte("one\n"))
+	w.Write([]byte("two\n"))
+	if err := w.(syncer).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("three\n"))
+	w.Write([]byte("four\n"))
+	if got := s.synced(); got != 1 {
+		t.Errorf("synced %d times, want 1", got)
+	}
+	w.Write([]byte("five\n"))
+	if got := s.synced(); got != 2 {
+		t.Errorf("synced %d times, want 2", got)
+	}
+}
+
+func TestFlushEveryNDisabled(t *testing.T) {
+	s := &syncCounter{}
+	if w := flushEvery(s, 0); w != s {
+		t.Error("zero messages between flushes wrapped the target")
+	}
+}
+
+func TestFlushEveryNFile(t *testing.T) {
+	dir := tempDir(t)
+	l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: dir + "/app.log", FlushEveryN: 2})
+	l.Info("one")
+	l.Info("two")
+	l.Info("three")
+
+	if lines := readLines(t, dir+"/app.log"); len(lines) != 3 {
+		t.Errorf("got %d lines, want 3", len(lines))
+	}
+}
+
+func TestSyncAtSeverity(t *testing.T) {
+	s := &syncCounter{}
+	if err := RegisterTarget("sync-counter", func(cfg LoggerConfig) (io.WriteCloser, error) { return s, nil }); err != nil {
+		t.Fatal(err)
+	}
+	l := newTestLog(t, LoggerConfig{LogType: "sync-counter", Severity: Verbose, SyncAtSeverity: Error})
+
+	for _, tc := range []struct {
+		severity LogSeverity
+		syncs    int
+	}{
+		{Debug, 0},
+		{Information, 0},
+		{Error, 1},
+		{Verbose, 1},
+		{Fatal, 2},
+	} {
+		l.Log(tc.severity, "message")
+		if got := s.synced(); got != tc.syncs {
+			t.Errorf("after %s message synced %d times, want %d", getSeverityName(tc.severity), got, tc.syncs)
+		}
+	}
+}
+
+func TestSyncAtSeverityDisabled(t *testing.T) {
+	s := &syncCounter{}
+	if err := RegisterTarget("sync-counter-disabled", func(cfg LoggerConfig) (io.WriteCloser, error) { return s, nil }); err != nil {
+		t.Fatal(err)
+	}
+	l := newTestLog(t, LoggerConfig{LogType: "sync-counter-disabled", Severity: Verbose})
+	l.Error("failed")
+
+	if got := s.synced(); got != 0 {
+		t.Errorf("synced %d times without SyncAtSeverity", got)
+	}
+}
+
+func TestSyncAtSeveritySharded(t *testing.T) {
+	dir := tempDir(t)
+	l := newTestLog(t, LoggerConfig{LogType: "sharded", Severity: Verbose, SyncAtSeverity: Error,
+		Paths: []string{dir + "/0.log", dir + "/1.log"}})
+
+	lg := l.loggers[0]
+	if len(lg.shardSyncs) != 2 || lg.shardSyncs[0] == nil || lg.shardSyncs[1] == nil {
+		t.Fatalf("shards got syncers %v", lg.shardSyncs)
+	}
+	shards := []*syncCounter{{}, {}}
+	lg.shardSyncs = []syncer{shards[0], shards[1]}
+
+	// round-robin writes Error into shard 0, Debug into 1, Error into 0 and Fatal into 1
+	for _, s := range []LogSeverity{Error, Debug, Error, Fatal} {
+		l.Log(s, "message")
+	}
+	if got := []int{shards[0].synced(), shards[1].synced()}; got[0] != 2 || got[1] != 1 {
+		t.Errorf("shards synced %v times, want [2 1]", got)
+	}
+	for _, p := range []string{dir + "/0.log", dir + "/1.log"} {
+		if lines := readLines(t, p); len(lines) != 2 {
+			t.Errorf("%s got %d lines, want 2", p, len(lines))
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message formats of the logger. Output of all formats is deterministic, so the same message
+// with the same fields is always rendered the same way (e.g. for golden file tests): JSON lines
+// start with time, level (and levelName), prefix and msg keys followed by fields, text and syslog
+// formats write fields after the message. Fields are ordered by key, and so are keys of nested
+// maps and structs.
+const (
+	// FormatText writes plain text lines
+	FormatText = "text"
+	// FormatJSON writes one JSON object per line
+	FormatJSON = "json"
+	// FormatTextJSON writes plain text lines followed by fields as compact JSON object
+	FormatTextJSON = "text+json"
+)
+
+// Severity encodings used in JSON format
+const (
+	// SeverityName writes severity name, e.g. "level":"INFO"
+	SeverityName = "name"
+	// SeverityNumber writes numeric severity, e.g. "level":40
+	SeverityNumber = "number"
+	// SeverityBoth writes both, e.g. "level":40,"levelName":"INFO"
+	SeverityBoth = "both"
+)
+
+const jsonTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+const (
+	levelNameKey = "levelName"
+	prefixKey    = "prefix"
+)
+
+// FieldKeys specifies names of the standard keys in JSON format, empty names
+// keep the defaults time, level and msg
+type FieldKeys struct {
+	Time    string `json:"time" yaml:"time"`
+	Level   string `json:"level" yaml:"level"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// resolveFieldKeys fills in default key names and checks the keys do not collide
+func resolveFieldKeys(keys FieldKeys, severityEncoding string, prefix string) (FieldKeys, error) {
+	if keys.Time == "" {
+		keys.Time = "time"
+	}
+	if keys.Level == "" {
+		keys.Level = "level"
+	}
+	if keys.Message == "" {
+		keys.Message = "msg"
+	}
+
+	used := []string{keys.Time, keys.Level, keys.Message}
+	if severityEncoding == SeverityBoth {
+		used = append(used, levelNameKey)
+	}
+	if prefix != "" {
+		used = append(used, prefixKey)
+	}
+
+	seen := make(map[string]bool, len(used))
+	for _, k := range used {
+		if seen[k] {
+			return FieldKeys{}, fmt.Errorf("field key %s is used more than once", k)
+		}
+		seen[k] = true
+	}
+
+	return keys, nil
+}
+
+// Severity styles of text formats
+const (
+	// SeverityStyleName writes severity name padded to the same width, e.g. "INFO   "
+	SeverityStyleName = "name"
+	// SeverityStyleLetter writes single letter of severity in brackets, e.g. "[I]"
+	SeverityStyleLetter = "letter"
+	// SeverityStyleEmoji writes emoji marking severity, e.g. "ℹ️", meant for screen loggers
+	// during local development
+	SeverityStyleEmoji = "emoji"
+)
+
+var severityEmoji = map[LogSeverity]string{
+	Fatal:       "🔥",
+	Error:       "❌",
+	Warning:     "⚠️",
+	Information: "ℹ️",
+	Debug:       "🐛",
+	Verbose:     "🔍",
+}
+
+func validSeverityStyle(style string) bool {
+	switch style {
+	case "", SeverityStyleName, SeverityStyleLetter, SeverityStyleEmoji:
+		return true
+	}
+
+	return false
+}
+
+// severityText renders severity in text formats according to the severity style,
+// names are padded to the same width only when separated from message by single space
+func (l *Logger) severityText(severity LogSeverity) string {
+	switch l.severityStyle {
+	case SeverityStyleLetter:
+		return l.colored(severity, "["+getLogTypeString(severity)[:1]+"]")
+	case SeverityStyleEmoji:
+		if e, ok := severityEmoji[severity]; ok {
+			return l.colored(severity, e)
+		}
+	}
+
+	if l.separator != " " {
+		return l.colored(severity, getSeverityName(severity))
+	}
+
+	return l.colored(severity, getLogTypeString(severity))
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case "", FormatText, FormatJSON, FormatTextJSON, FormatSyslog, FormatAuto:
+		return true
+	}
+
+	return false
+}
+
+func validSeverityEncoding(encoding string) bool {
+	switch encoding {
+	case "", SeverityName, SeverityNumber, SeverityBoth:
+		return true
+	}
+
+	return false
+}
+
+func getSeverityName(severity LogSeverity) string {
+	return strings.TrimSpace(getLogTypeString(severity))
+}
+
+func writeJSONValue(sb *strings.Builder, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	sb.Write(b)
+}
+
+func writeJSONField(sb *strings.Builder, key string, value interface{}) {
+	sb.WriteString(",")
+	writeJSONValue(sb, key)
+	sb.WriteString(":")
+	writeJSONValue(sb, value)
+}
+
+// encodeJSONFields renders fields as compact JSON object with keys sorted, preceded by space.
+// It returns empty string when there are no fields.
+func (l *Logger) encodeJSONFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	l.writeJSONFields(&sb, fields)
+
+	return " {" + strings.TrimPrefix(sb.String(), ",") + "}"
+}
+
+// encodeJSON renders message as JSON object with time, level and msg keys first
+// followed by fields sorted by key, fields named as the standard keys are prefixed by "fields."
+func (l *Logger) encodeJSON(t time.Time, severity LogSeverity, msg string, fields map[string]interface{}) string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	writeJSONValue(&sb, l.keys.Time)
+	sb.WriteString(":")
+	writeJSONValue(&sb, t.Format(jsonTimeLayout))
+
+	switch l.severityEncoding {
+	case SeverityNumber:
+		writeJSONField(&sb, l.keys.Level, severity)
+	case SeverityBoth:
+		writeJSONField(&sb, l.keys.Level, severity)
+		writeJSONField(&sb, levelNameKey, getSeverityName(severity))
+	default:
+		writeJSONField(&sb, l.keys.Level, getSeverityName(severity))
+	}
+
+	if l.prefix != "" {
+		writeJSONField(&sb, prefixKey, l.prefix)
+	}
+	writeJSONField(&sb, l.keys.Message, msg)
+
+	reserved := []string{l.keys.Time, l.keys.Level, l.keys.Message}
+	if l.severityEncoding == SeverityBoth {
+		reserved = append(reserved, levelNameKey)
+	}
+	if l.prefix != "" {
+		reserved = append(reserved, prefixKey)
+	}
+	l.writeJSONFields(&sb, fields, reserved...)
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// jsonEntry is message decoded from its JSON line by targets needing severity and fields
+type jsonEntry struct {
+	time     time.Time
+	severity LogSeverity
+	message  string
+	fields   map[string]interface{}
+}
+
+// decodeJSON decodes message written in JSON format with the given keys, severity may be
+// encoded by name or number
+func decodeJSON(p []byte, keys FieldKeys) (jsonEntry, error) {
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+
+	var m map[string]interface{}
+	if err := d.Decode(&m); err != nil {
+		return jsonEntry{}, err
+	}
+
+	var e jsonEntry
+	if s, ok := m[keys.Time].(string); ok {
+		e.time, _ = time.Parse(jsonTimeLayout, s)
+	}
+	switch v := m[keys.Level].(type) {
+	case json.Number:
+		n, _ := v.Int64()
+		e.severity = LogSeverity(n)
+	case string:
+		for s := Fatal; s <= Verbose; s += 10 {
+			if getSeverityName(s) == v {
+				e.severity = s
+			}
+		}
+	}
+	e.message, _ = m[keys.Message].(string)
+
+	for _, k := range []string{keys.Time, keys.Level, keys.Message, levelNameKey} {
+		delete(m, k)
+	}
+	e.fields = m
+
+	return e, nil
+}
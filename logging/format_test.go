@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeverityEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		encoding string
+		want     map[string]interface{}
+	}{
+		{"", map[string]interface{}{"level": "WARNING"}},
+		{SeverityName, map[string]interface{}{"level": "WARNING"}},
+		{SeverityNumber, map[string]interface{}{"level": float64(Warning)}},
+		{"Both", map[string]interface{}{"level": float64(Warning), levelNameKey: "WARNING"}},
+	} {
+		t.Run(tc.encoding, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatJSON, SeverityEncoding: tc.encoding})
+			l.Warning("disk low")
+
+			lines := exportLines(l)
+			if len(lines) != 1 {
+				t.Fatalf("got %d lines, want 1", len(lines))
+			}
+			var m map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]interface{}{"level": m["level"]}
+			if v, ok := m[levelNameKey]; ok {
+				got[levelNameKey] = v
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+
+			entry, err := decodeJSON([]byte(lines[0]), FieldKeys{Time: "time", Level: "level", Message: "msg"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entry.severity != Warning || entry.message != "disk low" {
+				t.Errorf("decoded severity %d and message %q", entry.severity, entry.message)
+			}
+		})
+	}
+}
+
+func TestInvalidSeverityEncoding(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{{LogType: "membuf", Severity: Information, Format: FormatJSON, SeverityEncoding: "code"}}})
+	if err == nil {
+		l.Close()
+		t.Fatal("invalid severity encoding accepted")
+	}
+}
+
+func TestSeparator(t *testing.T) {
+	for _, tc := range []struct {
+		name, separator, want string
+	}{
+		{"default", "", "ERROR   failed"},
+		{"tab", "\t", "ERROR\tfailed"},
+		{"pipe", " | ", "ERROR | failed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Separator: tc.separator})
+			l.Error("failed")
+
+			lines := exportLines(l)
+			if len(lines) != 1 || !strings.HasSuffix(lines[0], tc.want) {
+				t.Errorf("got %q, want suffix %q", lines, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSONStableOrder(t *testing.T) {
+	lg := &Logger{}
+	keys, err := resolveFieldKeys(FieldKeys{}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.keys = keys
+
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	fields := map[string]interface{}{"zone": "eu", "attempt": 3, "msg": "shadowed", "user": "u1", "b": true, "a": nil}
+	want := `{"time":"` + now.Format(jsonTimeLayout) + `","level":"ERROR","msg":"failed",` +
+		`"a":null,"attempt":3,"b":true,"fields.msg":"shadowed","user":"u1","zone":"eu"}`
+
+	for i := 0; i < 50; i++ {
+		if got := lg.encodeJSON(now, Error, "failed", fields); got != want {
+			t.Fatalf("run %d got %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestJSONStableOrderAcrossMessages(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatJSON})
+	fields := map[string]interface{}{"d": 4, "c": 3, "b": 2, "a": 1, "e": 5, "f": 6, "g": 7, "h": 8}
+	for i := 0; i < 20; i++ {
+		l.WithFields(fields).Info("message")
+	}
+
+	lines := exportLines(l)
+	if len(lines) != 20 {
+		t.Fatalf("got %d lines, want 20", len(lines))
+	}
+	first := lines[0][strings.Index(lines[0], `"level"`):]
+	if !strings.HasSuffix(first, `"msg":"message","a":1,"b":2,"c":3,"d":4,"e":5,"f":6,"g":7,"h":8}`) {
+		t.Errorf("fields are not sorted: %s", first)
+	}
+	for _, line := range lines[1:] {
+		if got := line[strings.Index(line, `"level"`):]; got != first {
+			t.Errorf("got %s, want %s", got, first)
+		}
+	}
+}
+
+func TestJSONRenamedKeysAreUnique(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy string
+		fields map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{"renamed key taken", "", map[string]interface{}{"msg": "a", "fields.msg": "b"},
+			map[string]interface{}{"fields.msg": "b", "fields.fields.msg": "a"}},
+		{"renamed key taken twice", "", map[string]interface{}{"level": 1, "fields.level": 2, "fields.fields.level": 3},
+			map[string]interface{}{"fields.level": float64(2), "fields.fields.level": float64(3), "fields.fields.fields.level": float64(1)}},
+		{"nil value", "", map[string]interface{}{"time": "t", "fields.time": nil},
+			map[string]interface{}{"fields.time": nil, "fields.fields.time": "t"}},
+		{"encode error field", EncodeErrorsField, map[string]interface{}{encodeErrorKey: "mine", "bad": badMarshaler{}},
+			map[string]interface{}{"fields." + encodeErrorKey: "mine", "bad": "bad"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatJSON, EncodeErrors: tc.policy})
+			l.WithFields(tc.fields).Info("message")
+
+			lines := exportLines(l)
+			if len(lines) != 1 {
+				t.Fatalf("got %d lines, want 1", len(lines))
+			}
+			// decoding into a map would hide repeated keys, so count them in the token stream
+			d := json.NewDecoder(strings.NewReader(lines[0]))
+			seen := map[string]int{}
+			d.Token()
+			for d.More() {
+				tok, _ := d.Token()
+				seen[tok.(string)]++
+				var v interface{}
+				d.Decode(&v)
+			}
+			for k, n := range seen {
+				if n != 1 {
+					t.Errorf("key %s written %d times in %s", k, n, lines[0])
+				}
+			}
+
+			var m map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+				t.Fatal(err)
+			}
+			if m["msg"] != "message" {
+				t.Errorf("got message %v", m["msg"])
+			}
+			for k, want := range tc.want {
+				if got, ok := m[k]; !ok || !reflect.DeepEqual(got, want) {
+					t.Errorf("got %s=%v, want %v in %s", k, got, want, lines[0])
+				}
+			}
+			if tc.policy == EncodeErrorsField {
+				if s, _ := m[encodeErrorKey].(string); !strings.HasPrefix(s, "bad: ") {
+					t.Errorf("got %s=%v", encodeErrorKey, m[encodeErrorKey])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// gzipFlushInterval is the longest time compressed messages stay buffered
+const gzipFlushInterval = time.Second
+
+// fileTarget is log file written directly or through compression
+type fileTarget interface {
+	io.WriteCloser
+	syncer
+}
+
+// gzipFile compresses messages written into the log file as gzip stream. Compressed data is
+// flushed every gzipFlushInterval, so the file can be read (e.g. by zcat) while it is being
+// written, and the stream is completed on Close.
+type gzipFile struct {
+	mu   sync.Mutex
+	file fileTarget
+	gz   *gzip.Writer
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newGzipFile(f fileTarget) *gzipFile {
+	g := &gzipFile{
+		file: f,
+		gz:   gzip.NewWriter(f),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go g.run()
+
+	return g
+}
+
+func (g *gzipFile) run() {
+	defer close(g.done)
+
+	t := time.NewTicker(gzipFlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			g.mu.Lock()
+			if g.gz != nil {
+				g.gz.Flush()
+			}
+			g.mu.Unlock()
+		case <-g.stop:
+			return
+		}
+	}
+}
+
+// Write implements io.Writer interface
+func (g *gzipFile) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.gz == nil {
+		return 0, fmt.Errorf("compressed log file is closed")
+	}
+
+	return g.gz.Write(p)
+}
+
+// Sync flushes compressed data and commits the file to stable storage
+func (g *gzipFile) Sync() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.gz == nil {
+		return nil
+	}
+
+	if err := g.gz.Flush(); err != nil {
+		return err
+	}
+
+	return g.file.Sync()
+}
+
+// Close implements io.Closer interface, the gzip stream is completed before the file is closed
+func (g *gzipFile) Close() error {
+	g.mu.Lock()
+	if g.gz == nil {
+		g.mu.Unlock()
+		return nil
+	}
+	err := g.gz.Close()
+	g.gz = nil
+	g.mu.Unlock()
+
+	close(g.stop)
+	<-g.done
+
+	if cerr := g.file.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// openLogFile creates log file compressed when requested and rotated when it reaches
+// maximum size
+func (l *Log) openLogFile(logFilePath string, rotate rotation, compress bool) (fileTarget, error) {
+	f, written, err := l.createTarget(logFilePath, rotate, compress)
+	if err != nil {
+		return nil, err
+	}
+
+	if rotate.maxBytes > 0 {
+		return &cappedFile{log: l, path: logFilePath, rotate: rotate, compress: compress, file: f, written: written}, nil
+	}
+
+	return f, nil
+}
+
+// createTarget creates log file compressed when requested, it returns number of bytes
+// written into the new file (by rotation marker)
+func (l *Log) createTarget(logFilePath string, rotate rotation, compress bool) (fileTarget, int64, error) {
+	lf, archive, err := l.createLogFile(logFilePath, rotate)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var f fileTarget = lf
+	if compress {
+		f = newGzipFile(lf)
+	}
+
+	var written int64
+	if archive != "" && rotate.marker != nil {
+		n, err := io.WriteString(f, rotate.marker(time.Now(), archive))
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		written = int64(n)
+	}
+
+	return f, written, nil
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// healthChecker is implemented by targets able to tell whether they can be written into
+type healthChecker interface {
+	health() error
+}
+
+// HealthCheck reports whether all targets of the loggers can be written into, e.g. for readiness
+// probes. Files must still exist at their paths (not removed or replaced) and network targets
+// must be connected. The check writes nothing, returned error describes all unhealthy targets.
+func (l *Log) HealthCheck() error {
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var errs []string
+	for i, lg := range l.loggers {
+		if hc, ok := lg.out.(healthChecker); ok {
+			if err := hc.health(); err != nil {
+				errs = append(errs, fmt.Sprintf("logger %d: %s", i, err.Error()))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unhealthy loggers: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// checkHealth checks health of closer c if it is able to
+func checkHealth(c interface{}) error {
+	if hc, ok := c.(healthChecker); ok {
+		return hc.health()
+	}
+
+	return nil
+}
+
+func (f *logFile) health() error {
+	opened, err := f.File.Stat()
+	if err != nil {
+		return err
+	}
+
+	current, err := os.Stat(f.Name())
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(opened, current) {
+		return fmt.Errorf("log file %s was replaced", f.Name())
+	}
+
+	return nil
+}
+
+func (t *templateFile) health() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return fmt.Errorf("log file %s is closed", t.current)
+	}
+
+	return checkHealth(t.file)
+}
+
+func (c *cappedFile) health() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return fmt.Errorf("log file %s is closed", c.path)
+	}
+
+	return checkHealth(c.file)
+}
+
+func (g *gzipFile) health() error {
+	return checkHealth(g.file)
+}
+
+func (w *netWriter) health() error {
+	if atomic.LoadInt32(&w.connected) == 0 {
+		return fmt.Errorf("network log target %s is not connected", w.address)
+	}
+
+	return nil
+}
+
+// health reports health of the opened target, target which was not opened yet is healthy
+func (w *lazyWriter) health() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return checkHealth(w.closer)
+}
+
+func (w *asyncWriter) health() error {
+	return checkHealth(w.closer)
+}
+
+func (m multiCloser) health() error {
+	var errs []string
+	for _, c := range m {
+		if err := checkHealth(c); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
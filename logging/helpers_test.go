@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// tempDir creates directory removed when the test finishes
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "logging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
+
+// redirectStd replaces os.Stdout and os.Stderr by files until the test finishes, it returns
+// their paths
+func redirectStd(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := tempDir(t)
+	out, err := os.Create(dir + "/stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errOut, err := os.Create(dir + "/stderr")
+	if err != nil {
+		out.Close()
+		t.Fatal(err)
+	}
+
+	prevOut, prevErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = out, errOut
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = prevOut, prevErr
+		out.Close()
+		errOut.Close()
+	})
+
+	return out.Name(), errOut.Name()
+}
+
+// unsetEnv removes environment variable until the test finishes
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+
+	if value, ok := os.LookupEnv(key); ok {
+		os.Unsetenv(key)
+		t.Cleanup(func() { os.Setenv(key, value) })
+	}
+}
+
+// silenceInternalLog drops diagnostics of the package until the test finishes
+func silenceInternalLog(t *testing.T) {
+	internalMu.Lock()
+	prev := internalLog
+	internalLog = nil
+	internalMu.Unlock()
+
+	t.Cleanup(func() { SetInternalLog(prev) })
+}
+
+// newTestLog sets up log with the given loggers and closes it when the test finishes
+func newTestLog(t *testing.T, loggers ...LoggerConfig) *Log {
+	t.Helper()
+
+	l := &Log{}
+	if err := l.SetupLoggers(LogConfig{Loggers: loggers}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	return l
+}
+
+// readLines returns non-empty lines of the file
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// exportLines returns messages kept by membuf loggers of the log
+func exportLines(l *Log) []string {
+	var lines []string
+	for _, line := range strings.Split(string(l.Export()), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// chdir changes working directory until the test finishes
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(prev) })
+}
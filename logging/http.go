@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// LogRequest writes access log entry of HTTP request with method, path, status and duration
+// fields added to the given fields, e.g. "GET /index.html 200" method=GET path=/index.html ...
+func (l *Log) LogRequest(severity LogSeverity, method, path string, status int, dur time.Duration,
+	fields map[string]interface{}) {
+	if l == nil {
+		return
+	}
+
+	fields = mergeFields(fields, map[string]interface{}{
+		"method":   method,
+		"path":     path,
+		"status":   status,
+		"duration": dur,
+	})
+	l.WithFields(fields).writeMessage(severity, fmt.Sprintf("%s %s %d", method, path, status))
+}
+
+// statusRecorder remembers status and size of the response written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// WriteHeader implements http.ResponseWriter interface
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter interface
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+
+	return n, err
+}
+
+// Flush implements http.Flusher interface, e.g. for server-sent events, it does nothing
+// when the wrapped writer cannot flush
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker interface, e.g. for websockets, hijacked request
+// is logged with status 101 unless the handler wrote another one
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer, so http.ResponseController reaches its other features
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// RequestLogger returns handler calling next and writing access log entry of each request
+// by LogRequest, with remote address and response size added as remote and bytes fields
+func (l *Log) RequestLogger(severity LogSeverity, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		l.LogRequest(severity, r.Method, r.URL.Path, status, time.Since(start), map[string]interface{}{
+			"remote": r.RemoteAddr,
+			"bytes":  rec.bytes,
+		})
+	})
+}
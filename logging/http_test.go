@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogger(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"status", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}, "GET /path 404"},
+		{"implicit status", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("body"))
+		}, "GET /path 200"},
+		{"flush", func(w http.ResponseWriter, r *http.Request) {
+			f, ok := w.(http.Flusher)
+			if !ok {
+				t.Fatal("writer does not implement http.Flusher")
+			}
+			w.Write([]byte("data: event\n\n"))
+			f.Flush()
+		}, "GET /path 200"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+			rec := httptest.NewRecorder()
+
+			l.RequestLogger(Information, tc.handler).ServeHTTP(rec, httptest.NewRequest("GET", "/path", nil))
+
+			lines := exportLines(l)
+			if len(lines) != 1 || !strings.Contains(lines[0], tc.want) {
+				t.Errorf("got %q, want entry %q", lines, tc.want)
+			}
+			if tc.name == "flush" && !rec.Flushed {
+				t.Error("response was not flushed")
+			}
+		})
+	}
+}
+
+func TestRequestLoggerHijack(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+
+	done := make(chan struct{})
+	logged := l.RequestLogger(Information, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("writer does not implement http.Hijacker")
+			return
+		}
+		conn, rw, err := h.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		rw.Flush()
+	}))
+	// the entry is written after the handler returns, which may be after the client got the response
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logged.ServeHTTP(w, r)
+		close(done)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("got status %d, want 101", resp.StatusCode)
+	}
+
+	<-done
+	lines := exportLines(l)
+	if len(lines) != 1 || !strings.Contains(lines[0], "GET /ws 101") {
+		t.Errorf("got %q, want hijacked request logged with status 101", lines)
+	}
+}
+
+func TestRequestLoggerHijackUnsupported(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information})
+
+	l.RequestLogger(Information, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			t.Error("hijacking recorder succeeded")
+		}
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"log"
+	"sync"
+)
+
+var (
+	internalMu  sync.RWMutex
+	internalLog ILog = newInternalLog()
+)
+
+// newInternalLog creates log writing warnings and errors into standard error
+func newInternalLog() *Log {
+	lg := &Logger{logType: Stderr, severity: Warning, baseSeverity: Warning, separator: " ",
+		screen: newScreenWriter(stderr)}
+	lg.rawLogger = log.New(lg.screen, "logging: ", logFlags)
+
+	return &Log{loggers: []*Logger{lg}}
+}
+
+// SetInternalLog sets log receiving diagnostics of the package itself (standard error by default),
+// nil silences them. Errors are written when a logger fails to write a message (including
+// asynchronous writes and database inserts).
+func SetInternalLog(l ILog) {
+	internalMu.Lock()
+	defer internalMu.Unlock()
+
+	internalLog = l
+}
+
+// internalErrorf writes error into the internal log unless the error comes from the internal
+// log itself (source), which would fail again
+func internalErrorf(source *Log, msg string, args ...interface{}) {
+	internalMu.RLock()
+	il := internalLog
+	internalMu.RUnlock()
+
+	if il == nil {
+		return
+	}
+	if l, ok := il.(*Log); ok && source != nil && l.base() == source {
+		return
+	}
+
+	il.Errorf(msg, args...)
+}
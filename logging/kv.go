@@ -0,0 +1,96 @@
+package logging
+
+import "fmt"
+
+// kvErrorKey is the field describing misuse of key/value arguments
+const kvErrorKey = "kv_error"
+
+// kvFields converts alternating key/value arguments into fields. Instead of panicking on
+// misuse, non-string keys are converted to strings, a missing value is set to nil and
+// kv_error field describes the problem.
+func kvFields(kv []interface{}) map[string]interface{} {
+	if len(kv) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, (len(kv)+1)/2)
+	var problem string
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", kv[i])
+			problem = fmt.Sprintf("key %s is not a string", key)
+		}
+
+		if i+1 == len(kv) {
+			fields[key] = nil
+			problem = fmt.Sprintf("key %s has no value", key)
+			break
+		}
+		fields[key] = kv[i+1]
+	}
+
+	if problem != "" {
+		fields[kvErrorKey] = problem
+	}
+
+	return fields
+}
+
+// writeMessageKV writes message with key/value arguments added to fields of the log,
+// the arguments are converted only when some logger accepts the message
+func (l *Log) writeMessageKV(severity LogSeverity, msg string, kv []interface{}) {
+	b := l.base()
+	if b == nil {
+		return
+	}
+
+	severity = b.escalate(b.remap(severity, func() string { return msg }), msg)
+	if !b.enabledLocked(severity, l.name) || !b.accept(severity, msg) {
+		return
+	}
+
+	b.mu.RLock()
+	fields, fieldsText := l.entryFields()
+	if len(kv) > 0 {
+		fields = mergeFields(fields, kvFields(kv))
+		fieldsText = formatFields(fields)
+	}
+	e := Event{Severity: severity, Message: msg, Fields: fields, Time: b.now()}
+	b.emit(&e, fieldsText, l)
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	publish(handlers, e)
+}
+
+// Fatalkv writes fatal message with alternating keys and values as fields into the log
+func (l *Log) Fatalkv(msg string, kv ...interface{}) {
+	l.writeMessageKV(Fatal, msg, kv)
+}
+
+// Errorkv writes error message with alternating keys and values as fields into the log,
+// e.g. Errorkv("request failed", "status", 500, "path", "/")
+func (l *Log) Errorkv(msg string, kv ...interface{}) {
+	l.writeMessageKV(Error, msg, kv)
+}
+
+// Warningkv writes warning message with alternating keys and values as fields into the log
+func (l *Log) Warningkv(msg string, kv ...interface{}) {
+	l.writeMessageKV(Warning, msg, kv)
+}
+
+// Infokv writes informational message with alternating keys and values as fields into the log
+func (l *Log) Infokv(msg string, kv ...interface{}) {
+	l.writeMessageKV(Information, msg, kv)
+}
+
+// Debugkv writes debug message with alternating keys and values as fields into the log
+func (l *Log) Debugkv(msg string, kv ...interface{}) {
+	l.writeMessageKV(Debug, msg, kv)
+}
+
+// Verbosekv writes verbose message with alternating keys and values as fields into the log
+func (l *Log) Verbosekv(msg string, kv ...interface{}) {
+	l.writeMessageKV(Verbose, msg, kv)
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// lazyWriter opens its target on the first write, it is not reopened once closed
+type lazyWriter struct {
+	mu     sync.Mutex
+	open   func() (io.Writer, io.Closer, error)
+	out    io.Writer
+	closer io.Closer
+	closed bool
+}
+
+// Write implements io.Writer interface
+func (w *lazyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.openLocked(); err != nil {
+		return 0, err
+	}
+
+	return w.out.Write(p)
+}
+
+// ensureOpen opens the target unless it is open already
+func (w *lazyWriter) ensureOpen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.openLocked()
+}
+
+// openLocked opens the target unless it is open already, it must be called with w.mu locked
+func (w *lazyWriter) openLocked() error {
+	if w.closed {
+		return fmt.Errorf("log target is closed")
+	}
+	if w.out != nil {
+		return nil
+	}
+
+	out, closer, err := w.open()
+	if err != nil {
+		return err
+	}
+	w.out, w.closer = out, closer
+
+	return nil
+}
+
+// Close implements io.Closer interface, target which was never opened is not created
+func (w *lazyWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.closer == nil {
+		return nil
+	}
+
+	err := w.closer.Close()
+	w.out, w.closer = nil, nil
+
+	return err
+}
+
+// Sync commits opened target to stable storage if it supports syncing
+func (w *lazyWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if s, ok := w.out.(syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestLazyWriteAfterClose(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		write  bool
+		opened int
+	}{
+		{"opened", true, 1},
+		{"never opened", false, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opened := 0
+			w := &lazyWriter{open: func() (io.Writer, io.Closer, error) {
+				opened++
+				return &buf, nopCloser{&buf}, nil
+			}}
+			if tc.write {
+				if _, err := w.Write([]byte("before\n")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := w.Write([]byte("after\n")); err == nil {
+				t.Error("write after close succeeded")
+			}
+			if err := w.ensureOpen(); err == nil {
+				t.Error("open after close succeeded")
+			}
+			if opened != tc.opened {
+				t.Errorf("target opened %d times, want %d", opened, tc.opened)
+			}
+			if bytes.Contains(buf.Bytes(), []byte("after")) {
+				t.Errorf("got %q", buf.String())
+			}
+		})
+	}
+}
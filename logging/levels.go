@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validLevels checks severities of the levels map
+func validLevels(levels map[string]LogSeverity) error {
+	for name, severity := range levels {
+		if !validSeverity(severity) {
+			return fmt.Errorf("%d is invalid severity of logger %s", severity, name)
+		}
+	}
+
+	return nil
+}
+
+// nameLevel returns severity set by the levels map for log with the name, the most specific
+// entry wins (e.g. db.pool before db). It returns 0 when no entry matches. It must be called
+// on the root log with l.mu locked for reading.
+func (l *Log) nameLevel(name string) LogSeverity {
+	if name == "" || len(l.levels) == 0 {
+		return 0
+	}
+
+	for {
+		if severity, ok := l.levels[name]; ok {
+			return severity
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return 0
+		}
+		name = name[:i]
+	}
+}
+
+// accepts reports whether the logger writes message of the severity, level of named log
+// overrides severity of the logger unless it is 0 or the logger ignores levels
+func (l *Logger) accepts(severity, level LogSeverity) bool {
+	if level != 0 && !l.config.IgnoreLevels {
+		return severity <= level
+	}
+
+	return l.severity >= severity
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNamedLevels(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers: []LoggerConfig{{LogType: "membuf", Severity: Information}},
+		Levels:  map[string]LogSeverity{"db": Debug, "http": Warning, "db.pool": Error},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, tc := range []struct {
+		log      *Log
+		severity LogSeverity
+		written  bool
+	}{
+		{l.Named("db"), Debug, true},
+		{l.Named("db"), Verbose, false},
+		{l.Named("http"), Information, false},
+		{l.Named("http"), Warning, true},
+		{l.Named("db").Named("pool"), Warning, false},
+		{l.Named("db").Named("pool"), Error, true},
+		{l.Named("db").Named("query"), Debug, true},
+		{l.Named("dbx"), Debug, false},
+		{l.Named("cache"), Debug, false},
+		{l.Named("cache"), Information, true},
+		{l, Debug, false},
+		{l, Information, true},
+	} {
+		before := len(exportLines(l))
+		tc.log.Log(tc.severity, "message")
+		if written := len(exportLines(l)) > before; written != tc.written {
+			t.Errorf("%s message of %q written %v, want %v", getSeverityName(tc.severity), tc.log.name, written, tc.written)
+		}
+	}
+}
+
+func TestNamedLevelsIgnored(t *testing.T) {
+	path := filepath.Join(tempDir(t), "errors.log")
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers: []LoggerConfig{
+			{LogType: "membuf", Severity: Information},
+			{LogType: "file", Severity: Error, Path: path, IgnoreLevels: true},
+		},
+		Levels: map[string]LogSeverity{"db": Verbose},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := l.Named("db")
+	db.Debug("query")
+	db.Error("failed")
+
+	if got := exportLines(l); len(got) != 2 {
+		t.Errorf("membuf got %q, want both messages", got)
+	}
+	l.Close()
+	if got := readLines(t, path); len(got) != 1 || !strings.Contains(got[0], "failed") {
+		t.Errorf("error-only file got %q", got)
+	}
+}
+
+func TestInvalidNamedLevel(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers: []LoggerConfig{{LogType: "membuf", Severity: Information}},
+		Levels:  map[string]LogSeverity{"db": 55},
+	})
+	if err == nil {
+		l.Close()
+		t.Fatal("invalid level accepted")
+	}
+}
+
+func TestNamedChildrenShareTarget(t *testing.T) {
+	for _, tc := range []struct {
+		format           string
+		db, http, dbPool string
+	}{
+		{FormatText, "logger=db ", "logger=http ", "logger=db.pool "},
+		{FormatJSON, `"logger":"db"`, `"logger":"http"`, `"logger":"db.pool"`},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: tc.format})
+			db := l.Named("db")
+			pool := db.Named("pool")
+			http := l.Named("http")
+
+			db.Info("query")
+			http.Info("request")
+			pool.Info("connection")
+			l.Info("root")
+
+			lines := exportLines(l)
+			if len(lines) != 4 {
+				t.Fatalf("got %d lines, want 4", len(lines))
+			}
+			for i, want := range []string{tc.db, tc.http, tc.dbPool} {
+				if !strings.Contains(lines[i]+" ", want) {
+					t.Errorf("line %q does not contain %s", lines[i], want)
+				}
+			}
+			if strings.Contains(lines[3], "logger") {
+				t.Errorf("root line %q has logger field", lines[3])
+			}
+		})
+	}
+}
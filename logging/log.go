@@ -1,11 +1,16 @@
 package logging
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -43,19 +48,118 @@ const (
 	File LogType = iota + 1
 	// Screen target
 	Screen
+	// Network target
+	Network
+	// Stderr target
+	Stderr
+	// Dual target writes JSON into file and text into standard error
+	Dual
+	// Custom target registered by RegisterTarget
+	Custom
+	// Sharded target distributes messages across several files
+	Sharded
+	// Membuf target keeps the most recent messages in memory
+	Membuf
+	// Console target writes warnings and more severe messages into standard error
+	// and the others into standard output
+	Console
 )
 
 // Logger type encapsulates work with raw logger to write log messages
 type Logger struct {
-	rawLogger *log.Logger
-	severity  LogSeverity
-	logType   LogType
-	prefix    string
+	rawLogger    *log.Logger
+	severity     LogSeverity
+	baseSeverity LogSeverity
+	schedule     []severityWindow
+	logType      LogType
+	prefix       string
+	out          io.Closer
+	screen       *screenWriter
+	errLogger    *log.Logger
+	errScreen    *screenWriter
+	dual         *dualWriter
+	config       LoggerConfig
+	shards       []*log.Logger
+	shardSyncs   []syncer
+	shardNext    uint32
+	syslog       *syslogHeader
+	keys         FieldKeys
+
+	format           string
+	severityEncoding string
+	severityStyle    string
+	dryRun           bool
+	enqueueTimeout   time.Duration
+	separator        string
+	sync             syncer
+	async            []*asyncWriter
+	membuf           *memBuffer
+	heavySeen        uint32
+	keyed            *keyedSampler
+	color            bool
+	colors           map[LogSeverity]string
+	priority         bool
+	encodeErrors     string
+	stats            *counters
+	newlines         string
+	indent           string
+	// ownTime is set when time of text lines is rendered from the cached clock
+	// instead of by the raw logger
+	ownTime bool
+	// held are paths of files written by the loggers being replaced, files of the logger
+	// with those paths are deferred until the replacement is committed
+	held     map[string]bool
+	deferred []*lazyWriter
 }
 
-// Log implements ILog interface and provides logging functionality
+// Log implements ILog interface and provides logging functionality.
+// Methods of nil *Log do nothing, so optional logging dependencies need not be initialized.
 type Log struct {
-	loggers []*Logger
+	counters counters
+	mu       sync.RWMutex
+	loggers  []*Logger
+	filters  []Filter
+	stop     chan struct{}
+	clock    *clock
+	// selfStats stops goroutine writing statistics summary
+	selfStats chan struct{}
+	// flushSignal stops goroutine flushing loggers on signal
+	flushSignal chan struct{}
+
+	// root is set in logs derived by WithFields, they share loggers and settings of the root
+	root       *Log
+	fields     map[string]interface{}
+	fieldsText string
+	// order lists keys of fields from the least recently added one
+	order []string
+	// configFields are keys of fields set by DefaultFields of the configuration
+	configFields []string
+	name         string
+	callerSkip   int
+	includeSeq   bool
+	includeGID   bool
+	tail         *tailBuffer
+	captures     []*Logger
+	handlers     []func(Event)
+	remaps       []severityRemap
+	escalations  []*escalation
+	levels       map[string]LogSeverity
+	once         sync.Map
+	colors       map[LogSeverity]string
+	redactor     ConfigRedactor
+
+	recordTemplate bool
+	trace          TraceSeverities
+
+	boost         *time.Timer
+	boostSeverity LogSeverity
+	boostPrior    map[*Logger]LogSeverity
+
+	// targetCtx is cancelled by Close to interrupt targets waiting to reconnect,
+	// it is guarded by targetMu as it is used while writing
+	targetMu     sync.Mutex
+	targetCtx    context.Context
+	targetCancel context.CancelFunc
 }
 
 // ILog interface provides common interface for logging
@@ -76,15 +180,201 @@ type ILog interface {
 	Verbosef(msg string, args ...interface{})
 }
 
-// LogConfig type provides logging configuration
+// LogConfig type provides logging configuration.
+// Loggers are read from either logger or loggers key in both JSON and YAML.
+// TimeZone (IANA name, local time zone by default) is used to evaluate severity schedules.
+// DefaultFields are appended to every message as key=value pairs.
+// IncludeSeq adds seq field with increasing sequence number to every message, gaps in
+// the sequence reveal dropped messages.
+// IncludeGoroutineID adds goroutine field with ID of the logging goroutine to every message.
+// It is meant for debugging concurrency and it is slow, as the ID is parsed from the stack trace.
+// TailSize sets number of the most recent messages kept in memory for DrainTail and Replay.
+// RecordTemplate adds template field with the format string to messages written by the formatted
+// methods (Errorf, Infof, ...), so messages can be grouped regardless of their arguments.
+// DryRun sets loggers up without opening their targets, messages are rendered and counted
+// in Stats (Messages, Bytes) but not written, e.g. to estimate log volume.
+// Encoders define named encoding settings (format, severity encoding and style, field keys)
+// shared by loggers referencing them by Encoder, so several targets need not repeat them.
+// ClockInterval (e.g. 5ms) enables cached clock refreshed at the interval and used to timestamp
+// messages instead of calling time.Now for each of them. It speeds up logging at very high rates
+// at the cost of precision: timestamps lag behind by up to the interval and messages written
+// within the same interval share the timestamp, their order is kept by the target though.
+// Levels map names of logs created by Named to severities overriding severities of the loggers
+// for messages of those logs, e.g. {"db": 50, "http": 30}. Nested names fall back to their
+// parent (db.pool uses level of db unless set), logs with unlisted names use the loggers' severities.
+// Loggers with IgnoreLevels keep their own severity for all logs.
+// SelfStatsInterval (e.g. 1m) writes summary of logging statistics at the interval as information
+// message "logging stats" with rate of messages per severity (per second since the previous
+// summary) and current numbers of filtered, dropped and sampled out messages as fields.
+// Profiles contain named overrides of the configuration selected by SetupProfile
+// (e.g. dev, prod), profiles nested in a profile are ignored.
 type LogConfig struct {
-	Loggers []struct {
-		LogType  string      `json:"logType" yaml:"logType"`
-		Severity LogSeverity `json:"severity" yaml:"severity"`
-		Rotate   bool        `json:"rotate" yaml:"rotate"`
-		Path     string      `json:"path" yaml:"path"`
-		Prefix   string      `json:"prefix" yaml:"prefix"`
-	} `json:"logger" yaml:"loggers"`
+	Loggers            []LoggerConfig           `json:"logger" yaml:"loggers"`
+	TimeZone           string                   `json:"timeZone" yaml:"timeZone"`
+	DefaultFields      map[string]string        `json:"defaultFields" yaml:"defaultFields"`
+	IncludeSeq         bool                     `json:"includeSeq" yaml:"includeSeq"`
+	TailSize           int                      `json:"tailSize" yaml:"tailSize"`
+	RecordTemplate     bool                     `json:"recordTemplate" yaml:"recordTemplate"`
+	Profiles           map[string]LogConfig     `json:"profiles" yaml:"profiles"`
+	DryRun             bool                     `json:"dryRun" yaml:"dryRun"`
+	Encoders           map[string]EncoderConfig `json:"encoders" yaml:"encoders"`
+	IncludeGoroutineID bool                     `json:"includeGoroutineId" yaml:"includeGoroutineId"`
+	ClockInterval      string                   `json:"clockInterval" yaml:"clockInterval"`
+	SelfStatsInterval  string                   `json:"selfStatsInterval" yaml:"selfStatsInterval"`
+	Levels             map[string]LogSeverity   `json:"levels" yaml:"levels"`
+}
+
+// LoggerConfig type provides configuration of a single logger.
+// Path of the file logger may contain date placeholders {YYYY}, {MM}, {DD} and {HH}
+// (e.g. /var/log/app-{YYYYMMDD}.log) which are expanded when the file is created.
+// Once the expanded path changes, logging continues into a newly created file.
+// Rotate renames existing log file before the new one is created, appending time formatted
+// by RotateSuffixFormat (Go time layout, 20060102150405 by default), e.g. 20060102150405.000000000
+// avoids collisions of files rotated within the same second. RotateDirFormat (Go time layout,
+// e.g. 2006/01) moves rotated files into date based subdirectories of the log file directory,
+// e.g. logs/2024/06/app.log.150405, to keep number of files per directory manageable.
+// Schedule optionally overrides Severity within the given time windows.
+// Network logger writes into Address using Protocol tcp, udp, unix or unixgram
+// (for unix sockets Address is the socket path). Failed stream connection is re-established
+// with increasing delay between attempts, Close interrupts the reconnection.
+// Logger of type both writes into screen and into file given by Path (rotation settings
+// apply to the file). Logger of type dual writes each message as JSON into file given
+// by Path and as text into standard error, the text is written only if the JSON was.
+// Async logger queues up to BufferSize messages (100 by default) and writes them
+// from its own goroutine, so a slow target does not block the others. OverflowPolicy
+// (block, drop-newest or drop-oldest, block by default) decides what happens when
+// the queue is full. EnqueueTimeout (e.g. 100ms) limits how long block policy waits,
+// the message is dropped afterwards (zero, the default, waits forever).
+// Format is text (default), json, text+json (text followed by fields as JSON object),
+// syslog-rfc5424 or auto (colored text when screen, stderr or console logger writes into
+// terminal, JSON otherwise, LOG_FORMAT environment variable overrides the choice and NO_COLOR
+// disables colors). Syslog frames use Facility (user-level messages by default) and
+// AppName (program name by default). SeverityEncoding (name, number or both, name
+// by default) controls how severity is written in JSON format. FieldKeys renames
+// the standard time, level and msg keys of JSON format (e.g. @timestamp for Elasticsearch).
+// PriorityPrefix starts lines of text formats with syslog severity level in angle brackets
+// as expected e.g. by systemd journal: <2> for fatal, <3> error, <4> warning, <6> information
+// and <7> for debug and verbose messages.
+// SeverityStyle selects how text formats write severity: name (default, e.g. "INFO   "),
+// letter (e.g. "[I]") or emoji (🔥 fatal, ❌ error, ⚠️ warning, ℹ️ information, 🐛 debug
+// and 🔍 verbose, not padded, so it suits screen loggers rather than parsed files).
+// Separator is written between severity and message in text formats (single space by
+// default), e.g. "\t" or " | " for column based parsers, severity names are padded only
+// with the default.
+// Sharded logger distributes messages across files given by Paths, either round-robin
+// (ShardBy round-robin, the default) or by hash of ShardField value (ShardBy hash). Each
+// shard is created and rotated independently using the other file settings.
+// On Windows, logger of type eventlog writes messages into the Event Log using AppName as
+// the event source. Its format defaults to json, which is the only one it accepts, as do
+// database loggers.
+// ShowCaller adds source location of the logging call (e.g. app/main.go:12) as caller field
+// in json and syslog formats and inlines it before the message in text formats.
+// Console logger writes warnings and more severe messages into standard error and the others
+// into standard output using the same format settings.
+// Membuf logger keeps the most recent messages in memory up to BufferBytes (1 MiB by default)
+// dropping the oldest ones, they are returned by Export.
+// Lazy logger creates its file (or connection) when the first message is written.
+// FlushEveryN syncs log file to disk after every N messages, bounding loss on crash to N
+// messages (0, the default, leaves syncing to the operating system). SyncAtSeverity syncs
+// the file right after writing message of that severity or more severe one (e.g. 20 to have
+// errors on disk), sharded logger syncs the shard written into, it does not apply to async loggers.
+// MaxRows and MaxAge (e.g. 720h) limit rows kept by database logger (built with database tag).
+// CompressLive writes log file as gzip stream (name the file e.g. app.log.gz), compressed data
+// is flushed every second and the stream is completed when the file is closed or switched.
+// CreateRetries sets how many times creating log file (or its directory) is retried on failure,
+// e.g. on network file systems, waiting CreateBackoff (100ms by default) doubled after each attempt.
+// SampleKeyField samples entries separately for each value of the field (e.g. tenant_id), keeping
+// every SampleKeyEvery-th entry of each value, so no value dominates the volume. Counters of at most
+// SampleKeys values (1000 by default) are kept, the least recently seen are forgotten. Entries
+// without the field always pass, the dropped ones are counted in Stats as SampledByKey.
+// Newlines selects how text formats write messages containing newlines (e.g. stack traces):
+// preserve (default) writes them as they are, escape replaces them by \n to keep each message
+// on a single line and indent prefixes continuation lines by NewlineIndent (tab by default).
+// JSON and syslog formats always escape newlines.
+// MaxFieldValueBytes limits length of string field values (e.g. SQL queries or payloads) written
+// with a message independently of the message itself (0, the default, is unlimited), longer values
+// are cut at character boundary and followed by "...".
+// EncodeErrors selects what JSON formats do with field values failing to encode (e.g. channels
+// or values whose MarshalJSON fails): fallback (default) writes the value rendered by %v, drop
+// leaves the field out and field writes the rendered value and adds _encode_error field
+// describing the failure. The failures are counted in Stats as EncodeErrors.
+// MaxFields limits number of fields written with a single message (0, the default, is unlimited),
+// the most recently added fields are kept (fields of the call and fields added by the log, then
+// fields of the logs from the last derived one, default fields last) and the dropped ones are
+// replaced by fields_truncated field with their number.
+// SampleEvery keeps only every N-th entry with at least SampleMinFields fields or with fields
+// rendered to at least SampleMinBytes bytes, smaller entries always pass (zero thresholds
+// are not applied). Entries dropped by the logger are counted in Stats as SampledOut.
+// RotateMarker writes marker line naming the rotated file as the first line of the new file,
+// e.g. "=== log rotated at 2024/06/01 00:00:00.000000, previous: app.log.20240601000000 ===",
+// JSON and dual loggers write it as an entry with msg "log rotated" and previous field and
+// syslog loggers do not write it.
+// DiskFullAction selects what file and dual loggers do when the disk is full: drop (default)
+// drops messages which do not fit, fallback writes them into standard error and retry pauses
+// for DiskFullPause (1s by default) up to 3 times before dropping the message, blocking the
+// writers meanwhile. Only the write finding the disk full retries, later messages are dropped
+// at once until a write succeeds again. Single warning is written into the internal log when the disk fills up,
+// dropped messages are counted in Stats as DroppedDiskFull.
+// MaxFileBytes caps size of log files of file and dual loggers (0, the default, is unlimited):
+// the file is rotated before a message would make it exceed the cap, even when Rotate is not
+// set, so a file is larger only when it holds a single bigger message. Uncompressed size counts
+// with CompressLive. Files rotated within the same second get numeric suffix (e.g. .1).
+// Encoder names encoder of LogConfig whose settings apply unless set by the logger.
+// IgnoreLevels keeps severity of the logger (including its schedule and boosts) for messages of
+// named logs listed in Levels of LogConfig, e.g. for error-only files or alerting targets.
+type LoggerConfig struct {
+	LogType            string           `json:"logType" yaml:"logType"`
+	Severity           LogSeverity      `json:"severity" yaml:"severity"`
+	Rotate             bool             `json:"rotate" yaml:"rotate"`
+	Path               string           `json:"path" yaml:"path"`
+	Prefix             string           `json:"prefix" yaml:"prefix"`
+	Schedule           []SeverityWindow `json:"schedule" yaml:"schedule"`
+	Protocol           string           `json:"protocol" yaml:"protocol"`
+	Address            string           `json:"address" yaml:"address"`
+	Async              bool             `json:"async" yaml:"async"`
+	BufferSize         int              `json:"bufferSize" yaml:"bufferSize"`
+	OverflowPolicy     string           `json:"overflowPolicy" yaml:"overflowPolicy"`
+	Format             string           `json:"format" yaml:"format"`
+	SeverityEncoding   string           `json:"severityEncoding" yaml:"severityEncoding"`
+	Lazy               bool             `json:"lazy" yaml:"lazy"`
+	Paths              []string         `json:"paths" yaml:"paths"`
+	ShardBy            string           `json:"shardBy" yaml:"shardBy"`
+	ShardField         string           `json:"shardField" yaml:"shardField"`
+	Facility           int              `json:"facility" yaml:"facility"`
+	AppName            string           `json:"appName" yaml:"appName"`
+	FieldKeys          FieldKeys        `json:"fieldKeys" yaml:"fieldKeys"`
+	SeverityStyle      string           `json:"severityStyle" yaml:"severityStyle"`
+	Encoder            string           `json:"encoder" yaml:"encoder"`
+	FlushEveryN        int              `json:"flushEveryN" yaml:"flushEveryN"`
+	MaxRows            int              `json:"maxRows" yaml:"maxRows"`
+	MaxAge             string           `json:"maxAge" yaml:"maxAge"`
+	EnqueueTimeout     string           `json:"enqueueTimeout" yaml:"enqueueTimeout"`
+	ShowCaller         bool             `json:"showCaller" yaml:"showCaller"`
+	Separator          string           `json:"separator" yaml:"separator"`
+	SyncAtSeverity     LogSeverity      `json:"syncAtSeverity" yaml:"syncAtSeverity"`
+	CompressLive       bool             `json:"compressLive" yaml:"compressLive"`
+	CreateRetries      int              `json:"createRetries" yaml:"createRetries"`
+	CreateBackoff      string           `json:"createBackoff" yaml:"createBackoff"`
+	BufferBytes        int              `json:"bufferBytes" yaml:"bufferBytes"`
+	RotateSuffixFormat string           `json:"rotateSuffixFormat" yaml:"rotateSuffixFormat"`
+	RotateDirFormat    string           `json:"rotateDirFormat" yaml:"rotateDirFormat"`
+	SampleMinFields    int              `json:"sampleMinFields" yaml:"sampleMinFields"`
+	SampleMinBytes     int              `json:"sampleMinBytes" yaml:"sampleMinBytes"`
+	SampleEvery        int              `json:"sampleEvery" yaml:"sampleEvery"`
+	RotateMarker       bool             `json:"rotateMarker" yaml:"rotateMarker"`
+	Newlines           string           `json:"newlines" yaml:"newlines"`
+	NewlineIndent      string           `json:"newlineIndent" yaml:"newlineIndent"`
+	MaxFields          int              `json:"maxFields" yaml:"maxFields"`
+	EncodeErrors       string           `json:"encodeErrors" yaml:"encodeErrors"`
+	MaxFieldValueBytes int              `json:"maxFieldValueBytes" yaml:"maxFieldValueBytes"`
+	SampleKeyField     string           `json:"sampleKeyField" yaml:"sampleKeyField"`
+	SampleKeyEvery     int              `json:"sampleKeyEvery" yaml:"sampleKeyEvery"`
+	SampleKeys         int              `json:"sampleKeys" yaml:"sampleKeys"`
+	PriorityPrefix     bool             `json:"priorityPrefix" yaml:"priorityPrefix"`
+	DiskFullAction     string           `json:"diskFullAction" yaml:"diskFullAction"`
+	DiskFullPause      string           `json:"diskFullPause" yaml:"diskFullPause"`
+	MaxFileBytes       int64            `json:"maxFileBytes" yaml:"maxFileBytes"`
+	IgnoreLevels       bool             `json:"ignoreLevels" yaml:"ignoreLevels"`
 }
 
 var logStrings = []string{
@@ -96,6 +386,11 @@ var logStrings = []string{
 	"VERBOSE",
 }
 
+// validSeverity reports whether severity is one of the defined severities
+func validSeverity(s LogSeverity) bool {
+	return s >= Fatal && s <= Verbose && s%10 == 0
+}
+
 func getLogTypeString(severity LogSeverity) string {
 	return logStrings[severity/10-1]
 }
@@ -104,92 +399,850 @@ func (l *Logger) logger() *log.Logger {
 	return l.rawLogger
 }
 
+// newRawLogger creates raw logger writing into w, JSON lines carry their own time and prefix
+// and so do text lines timestamped by the cached clock or starting with priority
+func (l *Logger) newRawLogger(w io.Writer) *log.Logger {
+	if l.format == FormatJSON || l.format == FormatSyslog {
+		return log.New(w, "", 0)
+	}
+
+	if l.priority {
+		return log.New(w, "", 0)
+	}
+
+	if l.ownTime {
+		return log.New(w, l.config.Prefix, 0)
+	}
+
+	return log.New(w, l.config.Prefix, logFlags)
+}
+
+// createLogDir creates directory of the log file, current directory of bare file names
+// (e.g. app.log) is not created.
 func (l *Log) createLogDir(path string) error {
+	if path == "" || path == "." {
+		return nil
+	}
+
 	_, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return os.MkdirAll(path, os.ModeDir|os.ModePerm)
+			return osMkdirAll(path, os.ModeDir|os.ModePerm)
 		}
 	}
 
 	return nil
 }
 
-func (l *Log) createLogFile(logFilePath string, rotate bool) (*os.File, error) {
+// createLogFile creates log file tracked by the open files guard, it returns path
+// the previous file was rotated to (empty when it was not)
+func (l *Log) createLogFile(logFilePath string, rotate rotation) (*logFile, string, error) {
+	if err := checkOpenFiles(); err != nil {
+		return nil, "", err
+	}
+
+	f, archive, err := l.createFile(logFilePath, rotate)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return newLogFile(f), archive, nil
+}
+
+func (l *Log) createFile(logFilePath string, rotate rotation) (*os.File, string, error) {
 	_, err := os.Stat(logFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return os.Create(logFilePath)
+			f, err := osCreate(logFilePath)
+			return f, "", err
+		}
+
+		return nil, "", err
+	}
+
+	var archive string
+	if rotate.enabled {
+		archive = uniqueArchive(rotate.archivePath(logFilePath, time.Now()))
+		if err := l.createLogDir(path.Dir(archive)); err != nil {
+			return nil, "", err
+		}
+
+		if err := os.Rename(logFilePath, archive); err != nil {
+			return nil, "", err
+		}
+	}
+
+	f, err := osCreate(logFilePath)
+
+	return f, archive, err
+}
+
+// expandLoggers replaces "both" loggers with screen and file logger sharing the same settings
+func expandLoggers(items []LoggerConfig) []LoggerConfig {
+	expanded := make([]LoggerConfig, 0, len(items))
+	for _, item := range items {
+		if strings.ToLower(item.LogType) != "both" {
+			expanded = append(expanded, item)
+			continue
+		}
+
+		screen, file := item, item
+		screen.LogType = "screen"
+		file.LogType = "file"
+		expanded = append(expanded, screen, file)
+	}
+
+	return expanded
+}
+
+// openTarget opens writer of the logger target. Returned closer is nil for targets
+// which must not be closed (e.g. screen).
+func (l *Log) openTarget(logType LogType, item LoggerConfig) (io.Writer, io.Closer, error) {
+	switch logType {
+	case Screen:
+		return newScreenWriter(stdout), nil, nil
+	case Stderr:
+		return newScreenWriter(stderr), nil, nil
+	case Network:
+		w, err := newNetWriter(l.targetContext(), item.Protocol, item.Address)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect log target: %s", err.Error())
+		}
+
+		return w, w, nil
+	case File, Dual:
+		var w io.Writer
+		var c io.Closer
+		err := retryCreate(item, func() error {
+			var err error
+			w, c, err = l.openFile(item)
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return w, c, nil
+	case Membuf:
+		return newMemBuffer(item.BufferBytes), nil, nil
+	case Custom:
+		factory, ok := targetFactory(item.LogType)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s is invalid log type", item.LogType)
+		}
+
+		w, err := factory(item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create log target: %s", err.Error())
+		}
+		if su, ok := w.(statsUser); ok {
+			su.setStats(&l.counters)
 		}
 
-		return nil, err
+		return w, w, nil
 	}
 
-	if rotate {
-		err := os.Rename(logFilePath, fmt.Sprintf("%s.%s", logFilePath, time.Now().Format("20060102150405")))
+	return nil, nil, fmt.Errorf("unsupported log type %d", logType)
+}
+
+// openFile creates log file of file and dual loggers
+func (l *Log) openFile(item LoggerConfig) (io.Writer, io.Closer, error) {
+	if hasPlaceholders(item.Path) {
+		tf, err := l.newTemplateFile(item.Path, newRotation(item), item.CompressLive)
 		if err != nil {
-			return nil, err
+			return nil, nil, fmt.Errorf("failed to create log file: %s", err.Error())
 		}
+
+		return flushEvery(l.diskFull(tf, item), item.FlushEveryN), tf, nil
+	}
+
+	logDir := path.Dir(item.Path)
+	if err := l.createLogDir(logDir); err != nil {
+		return nil, nil, fmt.Errorf("failed to create logging directory: %s", err.Error())
+	}
+
+	f, err := l.openLogFile(item.Path, newRotation(item), item.CompressLive)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create log file: %s", err.Error())
+	}
+
+	return flushEvery(l.diskFull(f, item), item.FlushEveryN), f, nil
+}
+
+// newWriter opens target of the logger wrapping it for lazy opening and asynchronous writing
+// as configured
+func (l *Log) newWriter(lg *Logger, item LoggerConfig) (io.Writer, io.Closer, error) {
+	if lg.dryRun {
+		return l.countBytes(ioutil.Discard, true), nil, nil
+	}
+
+	var w io.Writer
+	var c io.Closer
+	deferred := (lg.logType == File || lg.logType == Dual) && lg.held[pathKey(item.Path)]
+	if (item.Lazy || deferred) && lg.logType != Screen && lg.logType != Stderr && lg.logType != Membuf {
+		logType := lg.logType
+		lw := &lazyWriter{open: func() (io.Writer, io.Closer, error) {
+			return l.openTarget(logType, item)
+		}}
+		w, c = lw, lw
+		if deferred {
+			lg.deferred = append(lg.deferred, lw)
+		}
+	} else {
+		var err error
+		w, c, err = l.openTarget(lg.logType, item)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if sw, ok := w.(*screenWriter); ok {
+		lg.screen = sw
+	}
+
+	if mb, ok := w.(*memBuffer); ok {
+		lg.membuf = mb
+	}
+
+	if s, ok := w.(syncer); ok && !item.Async {
+		lg.sync = s
+	}
+
+	if item.Async {
+		aw := newAsyncWriter(w, c, item.BufferSize, item.OverflowPolicy, lg.enqueueTimeout, l)
+		w, c = aw, aw
+		lg.async = append(lg.async, aw)
 	}
 
-	return os.Create(logFilePath)
+	return l.countBytes(w, false), c, nil
 }
 
 // SetupLoggers method configures loggers to be used for logging
 func (l *Log) SetupLoggers(cfg LogConfig) error {
+	l = l.base()
+	if l == nil {
+		return fmt.Errorf("unable to setup loggers of nil log")
+	}
+
 	if cfg.Loggers == nil || len(cfg.Loggers) == 0 {
 		return fmt.Errorf("unable to setup loggers")
 	}
 
-	for _, item := range cfg.Loggers {
-		lg := &Logger{}
+	loc, err := loadTimeZone(cfg.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	clockInterval, err := parseClockInterval(cfg.ClockInterval)
+	if err != nil {
+		return err
+	}
+
+	if _, err := parseSelfStatsInterval(cfg.SelfStatsInterval); err != nil {
+		return err
+	}
+
+	if err := validLevels(cfg.Levels); err != nil {
+		return err
+	}
+
+	loggers, err := l.newLoggers(cfg, clockInterval, nil)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.loggers = append(l.loggers, loggers...)
+	l.applyConfig(cfg, loc, clockInterval)
+
+	return nil
+}
+
+// MustSetup returns new log with loggers configured by SetupLoggers and panics if it fails.
+// It is meant for application startup (e.g. main) where logging is required, libraries
+// should use SetupLoggers and handle the error.
+func MustSetup(cfg LogConfig) *Log {
+	l := &Log{}
+	if err := l.SetupLoggers(cfg); err != nil {
+		panic(fmt.Sprintf("logging: %s", err.Error()))
+	}
+
+	return l
+}
+
+// applyConfig applies log wide settings of the configuration, it must be called with l.mu locked
+func (l *Log) applyConfig(cfg LogConfig, loc *time.Location, clockInterval time.Duration) {
+	l.includeSeq = cfg.IncludeSeq
+	l.includeGID = cfg.IncludeGoroutineID
+	l.recordTemplate = cfg.RecordTemplate
+	if cfg.TailSize > 0 && l.tail == nil {
+		l.tail = newTailBuffer(cfg.TailSize)
+	}
+	if clockInterval > 0 && l.clock == nil {
+		l.clock = newClock(clockInterval)
+	}
+	l.startSchedule(loc)
+	l.applyColors()
+	if len(cfg.Levels) > 0 {
+		l.levels = make(map[string]LogSeverity, len(cfg.Levels))
+		for name, severity := range cfg.Levels {
+			l.levels[name] = severity
+		}
+	}
+	statsInterval, _ := parseSelfStatsInterval(cfg.SelfStatsInterval)
+	l.startSelfStats(statsInterval)
+
+	if len(cfg.DefaultFields) > 0 {
+		fields := make(map[string]interface{}, len(cfg.DefaultFields))
+		for k, v := range cfg.DefaultFields {
+			fields[k] = v
+			l.configFields = append(l.configFields, k)
+		}
+		l.order = appendOrder(l.order, fields)
+		l.setFields(mergeFields(l.fields, fields))
+	}
+}
+
+// newLoggers creates loggers of the configuration, loggers created before an error occurred
+// are closed
+func (l *Log) newLoggers(cfg LogConfig, clockInterval time.Duration, held map[string]bool) ([]*Logger, error) {
+	var loggers []*Logger
+	for _, item := range expandLoggers(cfg.Loggers) {
+		item, err := applyEncoder(item, cfg.Encoders)
+		if err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+		if item.Format == "" && jsonTarget(item.LogType) {
+			item.Format = FormatJSON
+		}
+
+		lg := &Logger{config: item, dryRun: cfg.DryRun, ownTime: clockInterval > 0, held: held}
 		switch strings.ToLower(item.LogType) {
 		case "file":
 			lg.logType = File
 		case "screen":
 			lg.logType = Screen
+		case "stderr":
+			lg.logType = Stderr
+		case "network":
+			lg.logType = Network
+		case "dual":
+			lg.logType = Dual
+		case "sharded":
+			lg.logType = Sharded
+		case "membuf":
+			lg.logType = Membuf
+		case "console":
+			lg.logType = Console
 		default:
-			return fmt.Errorf("%s is invalid log type", item.LogType)
+			if _, ok := targetFactory(item.LogType); !ok {
+				return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid log type", item.LogType))
+			}
+			lg.logType = Custom
+		}
+		if !validSeverity(item.Severity) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%d is invalid severity", item.Severity))
 		}
 		lg.severity = LogSeverity(item.Severity)
+		lg.baseSeverity = lg.severity
+
+		if item.SyncAtSeverity != 0 && !validSeverity(item.SyncAtSeverity) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%d is invalid sync severity", item.SyncAtSeverity))
+		}
+
+		if err := validRetries(item); err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		if err := validDiskFull(item); err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		if err := validRotateSuffix(item.RotateSuffixFormat); err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		if err := validRotateDir(item.RotateDirFormat); err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		if err := validHeavySampling(item); err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		lg.keyed, err = newKeyedSampler(item)
+		if err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		if item.MaxFieldValueBytes < 0 {
+			return nil, closeLoggers(loggers, fmt.Errorf("%d is invalid maximum size of field values", item.MaxFieldValueBytes))
+		}
+
+		if item.MaxFileBytes < 0 {
+			return nil, closeLoggers(loggers, fmt.Errorf("%d is invalid maximum size of log file", item.MaxFileBytes))
+		}
+
+		if item.MaxFields < 0 {
+			return nil, closeLoggers(loggers, fmt.Errorf("%d is invalid maximum number of fields", item.MaxFields))
+		}
+
+		if item.FlushEveryN < 0 {
+			return nil, closeLoggers(loggers, fmt.Errorf("%d is invalid number of messages between flushes", item.FlushEveryN))
+		}
+
+		if !validOverflowPolicy(item.OverflowPolicy) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid overflow policy", item.OverflowPolicy))
+		}
+
+		if item.EnqueueTimeout != "" {
+			lg.enqueueTimeout, err = time.ParseDuration(item.EnqueueTimeout)
+			if err != nil || lg.enqueueTimeout < 0 {
+				return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid enqueue timeout", item.EnqueueTimeout))
+			}
+		}
+
+		lg.format = strings.ToLower(item.Format)
+		if !validFormat(lg.format) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid log format", item.Format))
+		}
+		if lg.format == FormatAuto {
+			lg.format, lg.color = resolveAutoFormat(lg.logType)
+			// targets (rotation marker, custom targets) are opened with the resolved format
+			item.Format = lg.format
+			lg.config.Format = lg.format
+		}
+		lg.priority = item.PriorityPrefix && (lg.format == "" || lg.format == FormatText || lg.format == FormatTextJSON)
+
+		lg.severityEncoding = strings.ToLower(item.SeverityEncoding)
+		if !validSeverityEncoding(lg.severityEncoding) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid severity encoding", item.SeverityEncoding))
+		}
+
+		schedule, err := parseSchedule(item.Schedule)
+		if err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+		lg.schedule = schedule
+
+		if lg.format == FormatJSON || lg.format == FormatSyslog || lg.logType == Dual {
+			lg.prefix = item.Prefix
+		}
 
-		switch lg.logType {
-		case Screen:
-			lg.rawLogger = log.New(os.Stdout, item.Prefix, logFlags)
-		case File:
-			logDir := path.Dir(item.Path)
-			if err := l.createLogDir(logDir); err != nil {
-				return fmt.Errorf("failed to create logging directory: %s", err.Error())
+		lg.separator = item.Separator
+		if lg.separator == "" {
+			lg.separator = " "
+		}
+
+		lg.encodeErrors = strings.ToLower(item.EncodeErrors)
+		if !validEncodeErrors(lg.encodeErrors) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid encode errors policy", item.EncodeErrors))
+		}
+		lg.stats = &l.counters
+
+		lg.newlines = strings.ToLower(item.Newlines)
+		if !validNewlines(lg.newlines) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid newline handling", item.Newlines))
+		}
+		lg.indent = item.NewlineIndent
+		if lg.indent == "" {
+			lg.indent = defaultNewlineIndent
+		}
+
+		lg.severityStyle = strings.ToLower(item.SeverityStyle)
+		if !validSeverityStyle(lg.severityStyle) {
+			return nil, closeLoggers(loggers, fmt.Errorf("%s is invalid severity style", item.SeverityStyle))
+		}
+
+		lg.keys, err = resolveFieldKeys(item.FieldKeys, lg.severityEncoding, lg.prefix)
+		if err != nil {
+			return nil, closeLoggers(loggers, err)
+		}
+
+		if lg.format == FormatSyslog {
+			lg.syslog, err = newSyslogHeader(item, lg.logType)
+			if err != nil {
+				return nil, closeLoggers(loggers, err)
 			}
+		}
 
-			f, err := l.createLogFile(item.Path, item.Rotate)
+		if lg.logType == Sharded {
+			if err := l.setupShards(lg, item); err != nil {
+				return nil, closeLoggers(loggers, err)
+			}
+		} else if lg.logType == Console {
+			if err := l.setupConsole(lg, item); err != nil {
+				return nil, closeLoggers(loggers, err)
+			}
+		} else {
+			w, c, err := l.newWriter(lg, item)
 			if err != nil {
-				return fmt.Errorf("failed to create log file: %s", err.Error())
+				return nil, closeLoggers(loggers, err)
+			}
+
+			if lg.logType == Dual {
+				lg.format = FormatJSON
+				lg.screen = newScreenWriter(stderr)
+				lg.dual = &dualWriter{text: l.countBytes(lg.screen, lg.dryRun)}
 			}
 
-			lg.rawLogger = log.New(f, item.Prefix, logFlags)
+			lg.out = c
+			lg.rawLogger = lg.newRawLogger(w)
 		}
 
-		l.loggers = append(l.loggers, lg)
+		loggers = append(loggers, lg)
 	}
 
-	return nil
+	return loggers, nil
 }
 
-func (l *Log) writeMessage(severity LogSeverity, msg string) {
+// closeLoggers closes loggers and returns err, it is used to undo partial setup
+func closeLoggers(loggers []*Logger, err error) error {
+	for _, lg := range loggers {
+		if lg.out != nil {
+			lg.out.Close()
+		}
+	}
+
+	return err
+}
+
+// Close stops background processing, writes all queued messages and closes log files.
+// Log can be set up again afterwards.
+func (l *Log) Close() error {
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.cancelTargets()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+
+	if l.clock != nil {
+		l.clock.Stop()
+		l.clock = nil
+	}
+
+	if l.selfStats != nil {
+		close(l.selfStats)
+		l.selfStats = nil
+	}
+
+	if l.flushSignal != nil {
+		close(l.flushSignal)
+		l.flushSignal = nil
+	}
+
+	if l.boost != nil {
+		l.boost.Stop()
+		l.restoreBoost()
+	}
+
+	var errs []string
 	for _, lg := range l.loggers {
-		if lg.severity >= severity {
-			lg.logger().Printf("%s %s", getLogTypeString(severity), msg)
+		if lg.out == nil {
+			continue
+		}
+		if err := lg.out.Close(); err != nil {
+			errs = append(errs, err.Error())
 		}
 	}
+	l.loggers = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close loggers: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// writeMessage writes message into all loggers accepting the severity and returns their count
+func (l *Log) writeMessage(severity LogSeverity, msg string) int {
+	n, _, _ := l.write(severity, msg)
+
+	return n
+}
+
+// write writes message into all loggers accepting the severity and returns their count,
+// number of bytes written by all of them and the first write error
+func (l *Log) write(severity LogSeverity, msg string) (int, int, error) {
+	b := l.base()
+	if b == nil {
+		return 0, 0, nil
+	}
+
+	severity = b.escalate(b.remap(severity, func() string { return msg }), msg)
+	if !b.enabledLocked(severity, l.name) || !b.accept(severity, msg) {
+		return 0, 0, nil
+	}
+
+	b.mu.RLock()
+	fields, fieldsText := l.entryFields()
+	e := Event{Severity: severity, Message: msg, Fields: fields, Time: b.now()}
+	n, written, err := b.emit(&e, fieldsText, l)
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	publish(handlers, e)
+
+	return n, written, err
 }
 
 func (l *Log) writeMessagef(severity LogSeverity, msg string, args ...interface{}) {
-	for _, lg := range l.loggers {
-		if lg.severity >= severity {
-			lg.logger().Printf(fmt.Sprintf("%s %s", getLogTypeString(severity), msg), args...)
+	l.writef(severity, msg, args...)
+}
+
+// writef formats message and writes it as write does
+func (l *Log) writef(severity LogSeverity, msg string, args ...interface{}) (int, int, error) {
+	b := l.base()
+	if b == nil {
+		return 0, 0, nil
+	}
+
+	var text string
+	formatted := false
+	format := func() string {
+		if !formatted {
+			text, formatted = fmt.Sprintf(msg, args...), true
+		}
+		return text
+	}
+
+	severity = b.escalate(b.remap(severity, format), msg)
+	if !b.enabledLocked(severity, l.name) {
+		return 0, 0, nil
+	}
+
+	if !b.accept(severity, format()) {
+		return 0, 0, nil
+	}
+
+	b.mu.RLock()
+	fields, fieldsText := l.entryFields()
+	if b.recordTemplate {
+		fields = mergeFields(fields, map[string]interface{}{"template": msg})
+		fieldsText = formatFields(fields)
+	}
+	e := Event{Severity: severity, Message: text, Fields: fields, Time: b.now()}
+	n, written, err := b.emit(&e, fieldsText, l)
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	publish(handlers, e)
+
+	return n, written, err
+}
+
+// enabledLocked reports whether any logger accepts the severity from log with the name
+func (l *Log) enabledLocked(severity LogSeverity, name string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.enabled(severity, l.nameLevel(name))
+}
+
+// enabled reports whether any logger accepts the severity, level overrides severities of the
+// loggers unless it is 0. It must be called with l.mu locked for reading.
+func (l *Log) enabled(severity, level LogSeverity) bool {
+	for _, lg := range l.targets() {
+		if lg.accepts(severity, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emit writes event into all loggers accepting its severity and returns their count, number
+// of bytes written by all of them and the first write error. Fields added while writing (seq)
+// are stored into the event. Name of the writing log src selects its level from the levels map.
+// It must be called on the root log with l.mu locked for reading.
+func (l *Log) emit(e *Event, fieldsText string, src *Log) (int, int, error) {
+	severity, msg, now := e.Severity, e.Message, e.Time
+	if !validSeverity(severity) {
+		return 0, 0, fmt.Errorf("%d is invalid severity", severity)
+	}
+	level := l.nameLevel(src.name)
+
+	if l.includeSeq && l.enabled(severity, level) {
+		seq := atomic.AddUint64(&l.counters.seq, 1)
+		e.Fields = mergeFields(e.Fields, map[string]interface{}{"seq": seq})
+		fieldsText = formatFields(e.Fields)
+	}
+
+	if l.includeGID && l.enabled(severity, level) {
+		e.Fields = mergeFields(e.Fields, map[string]interface{}{"goroutine": goroutineID()})
+		fieldsText = formatFields(e.Fields)
+	}
+	fields := e.Fields
+
+	if l.tail != nil && l.enabled(severity, level) {
+		l.tail.record(*e)
+	}
+
+	n, total := 0, 0
+	var firstErr error
+	var caller string
+	var callerFields map[string]interface{}
+	for _, lg := range l.targets() {
+		if !lg.accepts(severity, level) {
+			continue
+		}
+
+		if lg.sampledOut(fields, fieldsText) {
+			atomic.AddUint64(&l.counters.sampledOut, 1)
+			continue
+		}
+
+		if lg.keyed != nil && lg.keyed.drop(fields) {
+			atomic.AddUint64(&l.counters.sampledByKey, 1)
+			continue
+		}
+
+		rl, fileSync := lg.logger(), lg.sync
+		if len(lg.shards) > 0 {
+			rl, fileSync = lg.shard(fields)
+		} else if lg.errLogger != nil && severity <= consoleSplit {
+			rl = lg.errLogger
 		}
+
+		// caller is inlined into text and added as field to structured formats
+		text, structured := msg, fields
+		if lg.config.ShowCaller {
+			if caller == "" {
+				caller = callerSite(src.callerSkip)
+				callerFields = mergeFields(fields, map[string]interface{}{callerKey: caller})
+			}
+			text, structured = caller+": "+msg, callerFields
+		}
+		text = lg.textMessage(text)
+
+		lineFields, lineText := fields, fieldsText
+		if max := lg.config.MaxFieldValueBytes; max > 0 {
+			structured, _ = truncateFields(structured, max)
+			if truncated, ok := truncateFields(lineFields, max); ok {
+				lineFields = truncated
+				lineText = formatFields(lineFields)
+			}
+		}
+		if max := lg.config.MaxFields; max > 0 && len(structured) > max {
+			order := src.fieldOrder()
+			structured = limitFields(structured, order, max)
+			if len(lineFields) > max {
+				lineFields = limitFields(lineFields, order, max)
+				lineText = formatFields(lineFields)
+			}
+		}
+
+		var written int
+		var err error
+		if lg.dual != nil {
+			written, err = lg.writeDual(now, severity, msg, text, structured, lineText)
+		} else if lg.format == FormatJSON {
+			written, err = output(rl, lg.encodeJSON(now, severity, msg, structured))
+		} else if lg.format == FormatSyslog {
+			written, err = lg.writeSyslog(rl.Writer(), lg.encodeSyslog(now, severity, msg, structured))
+		} else if lg.format == FormatTextJSON {
+			written, err = output(rl, lg.priorityText(severity)+lg.timeText(now)+lg.severityText(severity)+lg.separator+text+lg.encodeJSONFields(lineFields))
+		} else {
+			written, err = output(rl, lg.priorityText(severity)+lg.timeText(now)+lg.severityText(severity)+lg.separator+text+lineText)
+		}
+		if err == nil && fileSync != nil && severity <= lg.config.SyncAtSeverity {
+			err = fileSync.Sync()
+		}
+		if err != nil {
+			internalErrorf(l, "failed to write into %s logger: %s", strings.ToLower(lg.config.LogType), err.Error())
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		total += written
+		n++
+	}
+
+	if n > 0 {
+		atomic.AddUint64(&l.counters.messages[severity/10-1], 1)
+	}
+
+	return n, total, firstErr
+}
+
+// textHeaderSize is length of the time header written by raw loggers with logFlags
+var textHeaderSize = len("2006/01/02 15:04:05.000000 ")
+
+// output writes line by the raw logger and returns number of bytes it wrote, including
+// prefix, time header and newline added by the raw logger
+func output(rl *log.Logger, line string) (int, error) {
+	if err := rl.Output(3, line); err != nil {
+		return 0, err
+	}
+
+	n := len(rl.Prefix()) + len(line)
+	if rl.Flags()&logFlags != 0 {
+		n += textHeaderSize
+	}
+	if !strings.HasSuffix(line, "\n") {
+		n++
 	}
+
+	return n, nil
+}
+
+// Log writes message with the given severity into the log, invalid severity is rejected
+func (l *Log) Log(severity LogSeverity, msg string) error {
+	if !validSeverity(severity) {
+		return fmt.Errorf("%d is invalid severity", severity)
+	}
+
+	l.writeMessage(severity, msg)
+
+	return nil
+}
+
+// Logf writes formatted message with the given severity into the log, invalid severity is rejected
+func (l *Log) Logf(severity LogSeverity, msg string, args ...interface{}) error {
+	if !validSeverity(severity) {
+		return fmt.Errorf("%d is invalid severity", severity)
+	}
+
+	l.writeMessagef(severity, msg, args...)
+
+	return nil
+}
+
+// LogBytes writes message with the given severity into the log and returns number of bytes
+// written. The count is the sum over all targets accepting the severity, each rendering
+// the message in its own format including prefix, time header and newline, so it is not
+// the length of msg. Error is the first write error of any target, the remaining targets
+// are still written. Messages dropped by severity or filters write 0 bytes without error.
+func (l *Log) LogBytes(severity LogSeverity, msg string) (int, error) {
+	if !validSeverity(severity) {
+		return 0, fmt.Errorf("%d is invalid severity", severity)
+	}
+
+	_, n, err := l.write(severity, msg)
+
+	return n, err
+}
+
+// LogfBytes writes formatted message as LogBytes does
+func (l *Log) LogfBytes(severity LogSeverity, msg string, args ...interface{}) (int, error) {
+	if !validSeverity(severity) {
+		return 0, fmt.Errorf("%d is invalid severity", severity)
+	}
+
+	_, n, err := l.writef(severity, msg, args...)
+
+	return n, err
 }
 
 // Fatal writes fatal message into the log
@@ -212,8 +1265,18 @@ func (l *Log) Errorf(msg string, args ...interface{}) {
 	l.writeMessagef(Error, msg, args...)
 }
 
-// Errore writes error message into the log
+// Errore writes error message into the log, fields extracted from the error by extractors
+// registered by RegisterErrorExtractor are added to it
 func (l *Log) Errore(err error) {
+	if l == nil {
+		return
+	}
+
+	if fields := errorFields(err); len(fields) > 0 {
+		l.WithFields(fields).Error(err.Error())
+		return
+	}
+
 	l.Error(err.Error())
 }
 
@@ -256,3 +1319,34 @@ func (l *Log) Verbose(msg string) {
 func (l *Log) Verbosef(msg string, args ...interface{}) {
 	l.writeMessagef(Verbose, msg, args...)
 }
+
+// Fataln writes fatal message into the log and returns number of loggers which wrote it
+func (l *Log) Fataln(msg string) int {
+	return l.writeMessage(Fatal, msg)
+}
+
+// Errorn writes error message into the log and returns number of loggers which wrote it
+func (l *Log) Errorn(msg string) int {
+	return l.writeMessage(Error, msg)
+}
+
+// Warningn writes warning message into the log and returns number of loggers which wrote it
+func (l *Log) Warningn(msg string) int {
+	return l.writeMessage(Warning, msg)
+}
+
+// Infon writes informational message into the log and returns number of loggers which wrote it.
+// Zero means the message was filtered out by all loggers.
+func (l *Log) Infon(msg string) int {
+	return l.writeMessage(Information, msg)
+}
+
+// Debugn writes debug message into the log and returns number of loggers which wrote it
+func (l *Log) Debugn(msg string) int {
+	return l.writeMessage(Debug, msg)
+}
+
+// Verbosen writes verbose message into the log and returns number of loggers which wrote it
+func (l *Log) Verbosen(msg string) int {
+	return l.writeMessage(Verbose, msg)
+}
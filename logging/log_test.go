@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMustSetup(t *testing.T) {
+	l := MustSetup(LogConfig{Loggers: []LoggerConfig{{LogType: "membuf", Severity: Information}}})
+	defer l.Close()
+
+	l.Info("ready")
+	if lines := exportLines(l); len(lines) != 1 {
+		t.Errorf("got %d lines, want 1", len(lines))
+	}
+}
+
+func TestMustSetupPanics(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  LogConfig
+	}{
+		{"no loggers", LogConfig{}},
+		{"invalid log type", LogConfig{Loggers: []LoggerConfig{{LogType: "printer", Severity: Information}}}},
+		{"invalid severity", LogConfig{Loggers: []LoggerConfig{{LogType: "membuf", Severity: 15}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Fatal("MustSetup did not panic")
+				}
+				if msg, ok := r.(string); !ok || !strings.HasPrefix(msg, "logging: ") {
+					t.Errorf("panicked with %v", r)
+				}
+			}()
+
+			MustSetup(tc.cfg)
+		})
+	}
+}
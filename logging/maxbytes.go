@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// cappedFile rotates log file before a write would make it exceed maxBytes bytes, so no file
+// grows beyond the limit unless a single message is larger. Bytes are counted as written by
+// the logger, i.e. before compression. The check and the write are done under the same lock,
+// so concurrent writers cannot overshoot the limit.
+type cappedFile struct {
+	mu       sync.Mutex
+	log      *Log
+	path     string
+	rotate   rotation
+	compress bool
+	file     fileTarget
+	written  int64
+	closed   bool
+}
+
+// Write implements io.Writer interface
+func (c *cappedFile) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, fmt.Errorf("log file %s is closed", c.path)
+	}
+
+	// file is missing when the previous rotation failed to create it
+	if c.file == nil || c.written > 0 && c.written+int64(len(p)) > c.rotate.maxBytes {
+		if err := c.reopen(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := c.file.Write(p)
+	c.written += int64(n)
+
+	return n, err
+}
+
+// reopen closes the full file and starts a new one, the full file is always archived
+// even when the logger does not rotate files at start
+func (c *cappedFile) reopen() error {
+	if c.file != nil {
+		err := c.file.Close()
+		c.file = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	rotate := c.rotate
+	rotate.enabled = true
+	f, written, err := c.log.createTarget(c.path, rotate, c.compress)
+	if err != nil {
+		return err
+	}
+	c.file, c.written = f, written
+
+	return nil
+}
+
+// Close implements io.Closer interface
+func (c *cappedFile) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.file == nil {
+		return nil
+	}
+
+	err := c.file.Close()
+	c.file = nil
+
+	return err
+}
+
+// Sync commits the current file to stable storage
+func (c *cappedFile) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+
+	return c.file.Sync()
+}
+
+// uniqueArchive returns archive path not used by any existing file, so files rotated within
+// the resolution of the rotate suffix do not replace each other
+func uniqueArchive(archive string) string {
+	candidate := archive
+	for i := 1; ; i++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s.%d", archive, i)
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMaxFileBytesConcurrentWriters(t *testing.T) {
+	const maxBytes = 4096
+
+	for _, tc := range []struct {
+		name string
+		item LoggerConfig
+	}{
+		{"text", LoggerConfig{}},
+		{"json with marker", LoggerConfig{Format: FormatJSON, RotateMarker: true}},
+		{"async", LoggerConfig{Async: true, BufferSize: 64}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := tempDir(t)
+			item := tc.item
+			item.LogType, item.Severity, item.Path, item.MaxFileBytes = "file", Information, dir+"/app.log", maxBytes
+
+			l := &Log{}
+			if err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{item}}); err != nil {
+				t.Fatal(err)
+			}
+
+			const writers, messages = 8, 200
+			var wg sync.WaitGroup
+			for g := 0; g < writers; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < messages; i++ {
+						l.Infof("writer %d message %d %s", g, i, strings.Repeat("x", i%50))
+					}
+				}(g)
+			}
+			wg.Wait()
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) < 2 {
+				t.Fatalf("got %d files, want the log rotated", len(files))
+			}
+
+			lines := 0
+			for _, f := range files {
+				if f.Size() > maxBytes {
+					t.Errorf("%s has %d bytes, more than %d", f.Name(), f.Size(), maxBytes)
+				}
+				for _, line := range readLines(t, dir+"/"+f.Name()) {
+					if !strings.Contains(line, "log rotated") {
+						lines++
+					}
+				}
+			}
+			if lines != writers*messages {
+				t.Errorf("files hold %d messages, want %d", lines, writers*messages)
+			}
+		})
+	}
+}
+
+func TestMaxFileBytesHealth(t *testing.T) {
+	dir := tempDir(t)
+	l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: dir + "/app.log", MaxFileBytes: 1 << 20})
+
+	if err := l.HealthCheck(); err != nil {
+		t.Fatalf("health check of new file failed: %v", err)
+	}
+
+	if err := os.Remove(dir + "/app.log"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.HealthCheck(); err == nil {
+		t.Error("health check passed after the log file was removed")
+	}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+)
+
+const defaultBufferBytes = 1 << 20
+
+// memBuffer keeps the most recent messages up to the given number of bytes, whole oldest
+// messages are dropped to make room for new ones
+type memBuffer struct {
+	mu    sync.Mutex
+	buf   []byte
+	limit int
+}
+
+func newMemBuffer(limit int) *memBuffer {
+	if limit <= 0 {
+		limit = defaultBufferBytes
+	}
+
+	return &memBuffer{limit: limit}
+}
+
+// Write implements io.Writer interface
+func (b *memBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(p) >= b.limit {
+		b.buf = append(b.buf[:0], p[len(p)-b.limit:]...)
+		return len(p), nil
+	}
+
+	if over := len(b.buf) + len(p) - b.limit; over > 0 {
+		cut := over
+		if i := bytes.IndexByte(b.buf[over:], '\n'); i >= 0 {
+			cut += i + 1
+		}
+		b.buf = append(b.buf[:0], b.buf[cut:]...)
+	}
+	b.buf = append(b.buf, p...)
+
+	return len(p), nil
+}
+
+func (b *memBuffer) export() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf...)
+}
+
+// Export returns copy of messages kept by membuf loggers, e.g. to attach them to a crash report
+func (l *Log) Export() []byte {
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var out []byte
+	for _, lg := range l.loggers {
+		if lg.membuf != nil {
+			out = append(out, lg.membuf.export()...)
+		}
+	}
+
+	return out
+}
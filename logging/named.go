@@ -0,0 +1,22 @@
+package logging
+
+// loggerKey is the field carrying name of the log created by Named
+const loggerKey = "logger"
+
+// Named returns log which adds logger field with the name to every message, so messages of
+// components (e.g. plugins) sharing the same loggers can be told apart. Names of nested
+// named logs are joined by dot, e.g. plugins.auth.
+func (l *Log) Named(name string) *Log {
+	if l == nil {
+		return nil
+	}
+
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	d := l.WithFields(map[string]interface{}{loggerKey: name})
+	d.name = name
+
+	return d
+}
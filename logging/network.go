@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// netRetries limits reconnection attempts made by a single write
+const netRetries = 3
+
+// netBackoff is the delay before the first reconnection attempt, it doubles with each attempt
+const netBackoff = 100 * time.Millisecond
+
+// netWriter writes log messages into network connection (tcp, udp, unix or unixgram).
+// Stream connections are re-established when write fails. Close cancels pending
+// reconnection, so shutdown does not wait for the backoff.
+type netWriter struct {
+	mu       sync.Mutex
+	protocol string
+	address  string
+	conn     net.Conn
+	ctx      context.Context
+	cancel   context.CancelFunc
+	// connected mirrors conn != nil for health checks, which must not wait for mu
+	// while a write is reconnecting
+	connected int32
+}
+
+func newNetWriter(parent context.Context, protocol, address string) (*netWriter, error) {
+	protocol = strings.ToLower(protocol)
+	switch protocol {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("%s is invalid network protocol", protocol)
+	}
+
+	if address == "" {
+		return nil, fmt.Errorf("network address is not set")
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	w := &netWriter{protocol: protocol, address: address, ctx: ctx, cancel: cancel}
+	if err := w.dial(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *netWriter) stream() bool {
+	return strings.HasPrefix(w.protocol, "tcp") || w.protocol == "unix"
+}
+
+func (w *netWriter) dial() error {
+	var d net.Dialer
+	conn, err := d.DialContext(w.ctx, w.protocol, w.address)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	atomic.StoreInt32(&w.connected, 1)
+
+	return nil
+}
+
+// disconnect closes the current connection, caller holds mu
+func (w *netWriter) disconnect() error {
+	atomic.StoreInt32(&w.connected, 0)
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
+
+// reconnect dials the address again, retrying with increasing delay until netRetries
+// attempts fail or the writer is closed
+func (w *netWriter) reconnect() error {
+	delay := netBackoff
+	for attempt := 0; ; attempt++ {
+		err := w.dial()
+		if err == nil {
+			return nil
+		}
+		if attempt == netRetries {
+			return err
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-w.ctx.Done():
+			t.Stop()
+			return fmt.Errorf("network log target %s is closed", w.address)
+		}
+		delay *= 2
+	}
+}
+
+// Write implements io.Writer interface
+func (w *netWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if err := w.reconnect(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.conn.Write(p)
+	if err == nil || !w.stream() {
+		return n, err
+	}
+
+	w.disconnect()
+	if err := w.reconnect(); err != nil {
+		return 0, err
+	}
+
+	return w.conn.Write(p)
+}
+
+// Close implements io.Closer interface
+func (w *netWriter) Close() error {
+	w.cancel()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	return w.disconnect()
+}
+
+// targetContext returns context of targets opened by the log, it is cancelled by Close
+func (l *Log) targetContext() context.Context {
+	l.targetMu.Lock()
+	defer l.targetMu.Unlock()
+
+	if l.targetCtx == nil {
+		l.targetCtx, l.targetCancel = context.WithCancel(context.Background())
+	}
+
+	return l.targetCtx
+}
+
+// cancelTargets interrupts targets waiting to reconnect, so Close need not wait
+// for writes in progress to give up
+func (l *Log) cancelTargets() {
+	l.targetMu.Lock()
+	defer l.targetMu.Unlock()
+
+	if l.targetCancel != nil {
+		l.targetCancel()
+	}
+	l.targetCtx, l.targetCancel = nil, nil
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// backoffWriter returns network writer whose collector is gone, so the next write keeps
+// reconnecting with backoff
+func backoffWriter(t *testing.T, parent context.Context) *netWriter {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := newNetWriter(parent, "tcp", ln.Addr().String())
+	ln.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.disconnect()
+
+	return w
+}
+
+func TestNetworkCloseDuringBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		close func(w *netWriter, cancel context.CancelFunc)
+	}{
+		{"close", func(w *netWriter, cancel context.CancelFunc) { w.Close() }},
+		{"log shutdown", func(w *netWriter, cancel context.CancelFunc) { cancel() }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := backoffWriter(t, ctx)
+			defer w.Close()
+
+			written := make(chan error, 1)
+			go func() {
+				_, err := w.Write([]byte("message\n"))
+				written <- err
+			}()
+			// let the write fail its first attempt and wait for the backoff
+			time.Sleep(netBackoff / 2)
+
+			start := time.Now()
+			tc.close(w, cancel)
+			select {
+			case err := <-written:
+				if err == nil {
+					t.Error("write succeeded without collector")
+				}
+			case <-time.After(netBackoff):
+				t.Fatal("write kept retrying after close")
+			}
+			if elapsed := time.Since(start); elapsed >= netBackoff {
+				t.Errorf("close took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestNetworkHealthDuringBackoff(t *testing.T) {
+	w := backoffWriter(t, context.Background())
+	defer w.Close()
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := w.Write([]byte("message\n"))
+		written <- err
+	}()
+	// let the write fail its first attempt and wait for the backoff holding the lock
+	time.Sleep(netBackoff / 2)
+
+	checked := make(chan error, 1)
+	go func() { checked <- w.health() }()
+	select {
+	case err := <-checked:
+		if err == nil {
+			t.Error("reconnecting target reported healthy")
+		}
+	case <-time.After(netBackoff / 2):
+		t.Fatal("health check waited for reconnecting write")
+	}
+
+	w.Close()
+	<-written
+}
@@ -0,0 +1,45 @@
+package logging
+
+import "strings"
+
+// Handling of newlines within messages written in text formats
+const (
+	// NewlinesPreserve writes messages as they are, multi-line message spans several lines
+	NewlinesPreserve = "preserve"
+	// NewlinesEscape replaces newlines by \n (and carriage returns by \r), so every message
+	// is a single line
+	NewlinesEscape = "escape"
+	// NewlinesIndent prefixes continuation lines by NewlineIndent, so they can be told
+	// from the next message
+	NewlinesIndent = "indent"
+)
+
+// defaultNewlineIndent prefixes continuation lines when NewlineIndent is not set
+const defaultNewlineIndent = "\t"
+
+var newlineEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+func validNewlines(mode string) bool {
+	switch mode {
+	case "", NewlinesPreserve, NewlinesEscape, NewlinesIndent:
+		return true
+	}
+
+	return false
+}
+
+// textMessage renders message of text formats according to handling of newlines
+func (l *Logger) textMessage(msg string) string {
+	if !strings.ContainsAny(msg, "\r\n") {
+		return msg
+	}
+
+	switch l.newlines {
+	case NewlinesEscape:
+		return newlineEscaper.Replace(msg)
+	case NewlinesIndent:
+		return strings.Replace(strings.TrimRight(msg, "\n"), "\n", "\n"+l.indent, -1)
+	}
+
+	return msg
+}
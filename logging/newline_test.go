@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewlines(t *testing.T) {
+	msg := "panic: boom\ngoroutine 1:\n\tmain.main()\n"
+
+	for _, tc := range []struct {
+		name, mode, indent, format string
+		want                       []string
+	}{
+		{"preserve", "", "", FormatText, []string{"panic: boom", "goroutine 1:", "\tmain.main()"}},
+		{"escape", NewlinesEscape, "", FormatText, []string{`panic: boom\ngoroutine 1:\n` + "\tmain.main()" + `\n`}},
+		{"indent", NewlinesIndent, "", FormatText, []string{"panic: boom", "\tgoroutine 1:", "\t\tmain.main()"}},
+		{"custom indent", NewlinesIndent, "  | ", FormatText, []string{"panic: boom", "  | goroutine 1:", "  | \tmain.main()"}},
+		{"json", NewlinesPreserve, "", FormatJSON, []string{`"msg":"panic: boom\ngoroutine 1:\n\tmain.main()\n"`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: tc.format, Newlines: tc.mode, NewlineIndent: tc.indent})
+			l.Error(msg)
+
+			lines := exportLines(l)
+			if len(lines) != len(tc.want) {
+				t.Fatalf("got %d lines %q, want %d", len(lines), lines, len(tc.want))
+			}
+			if !strings.Contains(lines[0], tc.want[0]) {
+				t.Errorf("first line %q does not contain %q", lines[0], tc.want[0])
+			}
+			for i, want := range tc.want[1:] {
+				if lines[i+1] != want {
+					t.Errorf("line %d is %q, want %q", i+1, lines[i+1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidNewlines(t *testing.T) {
+	l := &Log{}
+	if err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{{LogType: "membuf", Severity: Information, Newlines: "fold"}}}); err == nil {
+		l.Close()
+		t.Fatal("invalid newline handling accepted")
+	}
+}
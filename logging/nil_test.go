@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	v := reflect.ValueOf(l)
+	typ := v.Type()
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		t.Run(m.Name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s panicked on nil log: %v", m.Name, r)
+				}
+			}()
+
+			ft := m.Type
+			args := []reflect.Value{v}
+			for j := 1; j < ft.NumIn(); j++ {
+				if ft.IsVariadic() && j == ft.NumIn()-1 {
+					break
+				}
+				args = append(args, zeroArg(ft.In(j)))
+			}
+			m.Func.Call(args)
+		})
+	}
+}
+
+// zeroArg returns zero value of the type, functions are stubs returning zero values since
+// calling a nil callback would panic regardless of the log
+func zeroArg(typ reflect.Type) reflect.Value {
+	if typ.Kind() != reflect.Func {
+		return reflect.Zero(typ)
+	}
+
+	return reflect.MakeFunc(typ, func([]reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, typ.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(typ.Out(i))
+		}
+		return out
+	})
+}
@@ -0,0 +1,31 @@
+package logging
+
+// Once writes message with the given severity only the first time it is called with the key,
+// e.g. for deprecation notices which should not repeat on every request. Keys are shared by
+// the log and logs derived from it and are kept until ResetOnce, so the set grows with every
+// distinct key: use fixed keys, not keys containing request data.
+func (l *Log) Once(key string, severity LogSeverity, msg string) {
+	b := l.base()
+	if b == nil {
+		return
+	}
+
+	if _, seen := b.once.LoadOrStore(key, struct{}{}); seen {
+		return
+	}
+
+	l.writeMessage(severity, msg)
+}
+
+// ResetOnce forgets keys of messages written by Once, so they are written again (e.g. in tests)
+func (l *Log) ResetOnce() {
+	b := l.base()
+	if b == nil {
+		return
+	}
+
+	b.once.Range(func(key, _ interface{}) bool {
+		b.once.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+const defaultMaxOpenFiles = 1000
+
+var (
+	openLogFiles int64
+	maxOpenFiles int64 = defaultMaxOpenFiles
+)
+
+// SetMaxOpenFiles sets maximum number of log files opened by the package at the same time
+// (1000 by default, 0 disables the check). Creating log file beyond the limit fails, which
+// usually reveals loggers being set up repeatedly without Close.
+func SetMaxOpenFiles(n int) {
+	atomic.StoreInt64(&maxOpenFiles, int64(n))
+}
+
+func checkOpenFiles() error {
+	limit := atomic.LoadInt64(&maxOpenFiles)
+	if open := atomic.LoadInt64(&openLogFiles); limit > 0 && open >= limit {
+		return fmt.Errorf("%d log files are already open, loggers are likely set up repeatedly without Close", open)
+	}
+
+	return nil
+}
+
+// logFile is log file counted by the open files guard until it is closed
+type logFile struct {
+	*os.File
+	closed int32
+}
+
+func newLogFile(f *os.File) *logFile {
+	atomic.AddInt64(&openLogFiles, 1)
+
+	return &logFile{File: f}
+}
+
+// Close implements io.Closer interface
+func (f *logFile) Close() error {
+	if atomic.CompareAndSwapInt32(&f.closed, 0, 1) {
+		atomic.AddInt64(&openLogFiles, -1)
+	}
+
+	return f.File.Close()
+}
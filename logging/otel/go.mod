@@ -0,0 +1,15 @@
+module github.com/mafalt/go-logging/logging/otel
+
+go 1.21
+
+require (
+	github.com/mafalt/go-logging v0.0.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	go.opentelemetry.io/otel v1.28.0 // indirect
+	golang.org/x/sys v0.0.0-20210423082822-04245dca01da // indirect
+)
+
+replace github.com/mafalt/go-logging => ../..
@@ -0,0 +1,31 @@
+// Package otel adds trace_id and span_id of the active OpenTelemetry span to messages of logs
+// returned by WithContext of package logging, importing it registers the extractor:
+//
+//	import _ "github.com/mafalt/go-logging/logging/otel"
+//
+// It is a separate module, so only applications importing it depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	"github.com/mafalt/go-logging/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	logging.RegisterContextExtractor(Fields)
+}
+
+// Fields returns trace_id and span_id of the span active in the context, nil when there is none
+func Fields(ctx context.Context) map[string]interface{} {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}
+}
@@ -0,0 +1,38 @@
+package otel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mafalt/go-logging/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithContextSpan(t *testing.T) {
+	l := &logging.Log{}
+	if err := l.SetupLoggers(logging.LogConfig{Loggers: []logging.LoggerConfig{{LogType: "membuf", Severity: logging.Information}}}); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:  trace.SpanID{0x04, 0x05},
+	})
+	l.WithContext(trace.ContextWithSpanContext(context.Background(), sc)).Info("traced")
+	l.WithContext(context.Background()).Info("untraced")
+
+	lines := strings.Split(strings.TrimSpace(string(l.Export())), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	for _, want := range []string{"trace_id=" + sc.TraceID().String(), "span_id=" + sc.SpanID().String()} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("line %q does not contain %s", lines[0], want)
+		}
+	}
+	if strings.Contains(lines[1], "trace_id") {
+		t.Errorf("line %q without span contains trace_id", lines[1])
+	}
+}
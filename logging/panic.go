@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"os"
+	"runtime/debug"
+)
+
+// exit terminates the process after unhandled panic was logged
+var exit = os.Exit
+
+// InstallPanicHandler returns function which recovers unhandled panic, writes the panic value
+// with complete stack trace as fatal message, closes the log and exits with status 1.
+// It has to be deferred directly at the top of main:
+//
+//	defer logging.InstallPanicHandler(log)()
+func InstallPanicHandler(l *Log) func() {
+	return func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		l.Fatalf("panic: %v\n%s", r, debug.Stack())
+		l.Close()
+		exit(1)
+	}
+}
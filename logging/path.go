@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pathPlaceholders maps date placeholders allowed in the log file path to time layouts
+var pathPlaceholders = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"MM", "01"},
+	{"DD", "02"},
+	{"HH", "15"},
+}
+
+// expandPath replaces date placeholders in the log file path, e.g. /var/log/app-{YYYYMMDD}.log.
+// Placeholder group is enclosed in braces and may combine {YYYY}, {MM}, {DD} and {HH} tokens.
+// Groups containing anything else are left untouched.
+func expandPath(p string, t time.Time) string {
+	var sb strings.Builder
+	for {
+		start := strings.Index(p, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(p[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		sb.WriteString(p[:start])
+		if layout, ok := placeholderLayout(p[start+1 : end]); ok {
+			sb.WriteString(t.Format(layout))
+		} else {
+			sb.WriteString(p[start : end+1])
+		}
+		p = p[end+1:]
+	}
+	sb.WriteString(p)
+
+	return sb.String()
+}
+
+func placeholderLayout(group string) (string, bool) {
+	if group == "" {
+		return "", false
+	}
+
+	var layout strings.Builder
+	for group != "" {
+		found := false
+		for _, ph := range pathPlaceholders {
+			if strings.HasPrefix(group, ph.token) {
+				layout.WriteString(ph.layout)
+				group = group[len(ph.token):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", false
+		}
+	}
+
+	return layout.String(), true
+}
+
+func hasPlaceholders(p string) bool {
+	return expandPath(p, time.Time{}) != p
+}
+
+// templateFile writes into the file expanded from the path template and switches
+// to a newly created file once the expanded path changes (e.g. on the next day)
+type templateFile struct {
+	mu       sync.Mutex
+	log      *Log
+	template string
+	rotate   rotation
+	compress bool
+	current  string
+	file     fileTarget
+}
+
+func (l *Log) newTemplateFile(template string, rotate rotation, compress bool) (*templateFile, error) {
+	tf := &templateFile{log: l, template: template, rotate: rotate, compress: compress}
+	if err := tf.open(expandPath(template, time.Now())); err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
+
+func (t *templateFile) open(logFilePath string) error {
+	if err := t.log.createLogDir(path.Dir(logFilePath)); err != nil {
+		return err
+	}
+
+	f, err := t.log.openLogFile(logFilePath, t.rotate, t.compress)
+	if err != nil {
+		return err
+	}
+
+	if t.file != nil {
+		t.file.Close()
+	}
+	t.file = f
+	t.current = logFilePath
+
+	return nil
+}
+
+// Write implements io.Writer interface
+func (t *templateFile) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return 0, fmt.Errorf("log file %s is closed", t.current)
+	}
+
+	if next := expandPath(t.template, time.Now()); next != t.current {
+		if err := t.open(next); err != nil {
+			return 0, err
+		}
+	}
+
+	return t.file.Write(p)
+}
+
+// Close implements io.Closer interface
+func (t *templateFile) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+
+	err := t.file.Close()
+	t.file = nil
+
+	return err
+}
+
+// Sync commits the current file to stable storage
+func (t *templateFile) Sync() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+
+	return t.file.Sync()
+}
@@ -0,0 +1,80 @@
+package logging
+
+import "fmt"
+
+// profileConfig returns base configuration overridden by the named profile:
+// loggers of the profile replace the base loggers as a whole, time zone, tail size, clock
+// and self stats intervals override base values when set, default fields, encoders and
+// levels are merged with profile values winning and IncludeSeq, IncludeGoroutineID,
+// RecordTemplate and DryRun are enabled when set in either of them
+func profileConfig(cfg LogConfig, name string) (LogConfig, error) {
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		return LogConfig{}, fmt.Errorf("logging profile %s does not exist", name)
+	}
+
+	merged := cfg
+	merged.Profiles = nil
+
+	if len(profile.Loggers) > 0 {
+		merged.Loggers = profile.Loggers
+	}
+	if profile.TimeZone != "" {
+		merged.TimeZone = profile.TimeZone
+	}
+	if profile.TailSize > 0 {
+		merged.TailSize = profile.TailSize
+	}
+	if profile.ClockInterval != "" {
+		merged.ClockInterval = profile.ClockInterval
+	}
+	if profile.SelfStatsInterval != "" {
+		merged.SelfStatsInterval = profile.SelfStatsInterval
+	}
+	merged.IncludeSeq = cfg.IncludeSeq || profile.IncludeSeq
+	merged.RecordTemplate = cfg.RecordTemplate || profile.RecordTemplate
+	merged.DryRun = cfg.DryRun || profile.DryRun
+	merged.IncludeGoroutineID = cfg.IncludeGoroutineID || profile.IncludeGoroutineID
+
+	if len(profile.DefaultFields) > 0 {
+		merged.DefaultFields = make(map[string]string, len(cfg.DefaultFields)+len(profile.DefaultFields))
+		for k, v := range cfg.DefaultFields {
+			merged.DefaultFields[k] = v
+		}
+		for k, v := range profile.DefaultFields {
+			merged.DefaultFields[k] = v
+		}
+	}
+
+	if len(profile.Levels) > 0 {
+		merged.Levels = make(map[string]LogSeverity, len(cfg.Levels)+len(profile.Levels))
+		for k, v := range cfg.Levels {
+			merged.Levels[k] = v
+		}
+		for k, v := range profile.Levels {
+			merged.Levels[k] = v
+		}
+	}
+
+	if len(profile.Encoders) > 0 {
+		merged.Encoders = make(map[string]EncoderConfig, len(cfg.Encoders)+len(profile.Encoders))
+		for k, v := range cfg.Encoders {
+			merged.Encoders[k] = v
+		}
+		for k, v := range profile.Encoders {
+			merged.Encoders[k] = v
+		}
+	}
+
+	return merged, nil
+}
+
+// SetupProfile configures loggers using base configuration overridden by the named profile
+func (l *Log) SetupProfile(cfg LogConfig, name string) error {
+	merged, err := profileConfig(cfg, name)
+	if err != nil {
+		return err
+	}
+
+	return l.SetupLoggers(merged)
+}
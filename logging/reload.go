@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Reload replaces all loggers of the log by loggers created from the configuration, e.g. when
+// configuration file changes. New loggers are created first, if any of them fails the already
+// created ones are closed and the log keeps writing into the current loggers. Otherwise the
+// loggers are swapped under write lock, so every message is written either into the old set
+// or into the new one, and the old loggers are closed (queued messages are written).
+// Log wide settings (IncludeSeq, TailSize, ClockInterval, ...) replace the current ones, so
+// default fields and levels missing in the configuration are removed, and active severity
+// boost is cancelled. Log files are created as by SetupLoggers, so a file kept
+// by the new configuration is truncated unless Rotate is set. Files written by the current
+// loggers are created only after the swap, so failed Reload leaves them untouched, error
+// creating them is returned though the new loggers stay in place (they retry on write).
+func (l *Log) Reload(cfg LogConfig) error {
+	l = l.base()
+	if l == nil {
+		return fmt.Errorf("unable to reload loggers of nil log")
+	}
+
+	if len(cfg.Loggers) == 0 {
+		return fmt.Errorf("unable to reload loggers")
+	}
+
+	loc, err := loadTimeZone(cfg.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	clockInterval, err := parseClockInterval(cfg.ClockInterval)
+	if err != nil {
+		return err
+	}
+
+	if _, err := parseSelfStatsInterval(cfg.SelfStatsInterval); err != nil {
+		return err
+	}
+
+	if err := validLevels(cfg.Levels); err != nil {
+		return err
+	}
+
+	// new targets get their own context, so closing the old ones does not interrupt them
+	l.mu.RLock()
+	held := heldPaths(l.loggers)
+	l.mu.RUnlock()
+
+	oldCtx, oldCancel := l.detachTargets()
+	loggers, err := l.newLoggers(cfg, clockInterval, held)
+	if err != nil {
+		l.restoreTargets(oldCtx, oldCancel)
+		return err
+	}
+	if oldCancel != nil {
+		oldCancel()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.boost != nil {
+		l.boost.Stop()
+		l.restoreBoost()
+	}
+
+	old := l.loggers
+	l.loggers = loggers
+	l.resetConfig()
+	l.applyConfig(cfg, loc, clockInterval)
+
+	err = l.closeOld(old)
+	if openErr := openDeferred(loggers); openErr != nil && err == nil {
+		err = openErr
+	}
+
+	return err
+}
+
+// pathKey normalizes path of log file, so paths referring to the same file compare equal
+func pathKey(p string) string {
+	if p == "" {
+		return ""
+	}
+
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(p)
+}
+
+// heldPaths returns paths of files written by the loggers, it must be called with l.mu locked
+func heldPaths(loggers []*Logger) map[string]bool {
+	held := make(map[string]bool)
+	for _, lg := range loggers {
+		switch lg.logType {
+		case File, Dual:
+			held[pathKey(lg.config.Path)] = true
+		case Sharded:
+			for _, p := range lg.config.Paths {
+				held[pathKey(p)] = true
+			}
+		}
+	}
+
+	return held
+}
+
+// openDeferred opens files of the loggers deferred until the loggers they replace are closed,
+// files opened later (e.g. by SetPath) are no longer deferred
+func openDeferred(loggers []*Logger) error {
+	var errs []string
+	for _, lg := range loggers {
+		for _, lw := range lg.deferred {
+			if err := lw.ensureOpen(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		lg.deferred, lg.held = nil, nil
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to open reloaded loggers: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// resetConfig discards log wide settings applied from the replaced configuration, so settings
+// missing in the new one do not stay in effect, and stops goroutines started for them (they
+// are started again with the new settings). Fields set by SetVersion are kept. Must be called
+// with l.mu locked.
+func (l *Log) resetConfig() {
+	l.levels = nil
+
+	if len(l.configFields) > 0 {
+		fields := mergeFields(l.fields, nil)
+		for _, k := range l.configFields {
+			delete(fields, k)
+		}
+		l.setFields(fields)
+		l.configFields = nil
+	}
+
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+
+	if l.clock != nil {
+		l.clock.Stop()
+		l.clock = nil
+	}
+
+	if l.selfStats != nil {
+		close(l.selfStats)
+		l.selfStats = nil
+	}
+}
+
+// closeOld closes replaced loggers, it must be called with l.mu locked
+func (l *Log) closeOld(old []*Logger) error {
+	var errs []string
+	for _, lg := range old {
+		if lg.out == nil {
+			continue
+		}
+		if err := lg.out.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close replaced loggers: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// detachTargets returns context of the current targets, targets created afterwards
+// get a new one
+func (l *Log) detachTargets() (context.Context, context.CancelFunc) {
+	l.targetMu.Lock()
+	defer l.targetMu.Unlock()
+
+	ctx, cancel := l.targetCtx, l.targetCancel
+	l.targetCtx, l.targetCancel = nil, nil
+
+	return ctx, cancel
+}
+
+// restoreTargets cancels context of targets created since detachTargets and restores
+// context of the previous targets
+func (l *Log) restoreTargets(ctx context.Context, cancel context.CancelFunc) {
+	l.targetMu.Lock()
+	defer l.targetMu.Unlock()
+
+	if l.targetCancel != nil {
+		l.targetCancel()
+	}
+	l.targetCtx, l.targetCancel = ctx, cancel
+}
@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReloadReplacesLogSettings(t *testing.T) {
+	membuf := LoggerConfig{LogType: "membuf", Severity: Error}
+
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers:       []LoggerConfig{membuf},
+		DefaultFields: map[string]string{"env": "old", "app": "api"},
+		Levels:        map[string]LogSeverity{"db": Debug},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.SetVersion("1.0")
+
+	err = l.Reload(LogConfig{
+		Loggers:       []LoggerConfig{membuf},
+		DefaultFields: map[string]string{"app": "worker"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Named("db").Debug("db debug")
+	l.Error("failed")
+
+	lines := exportLines(l)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines %q, want only the error", len(lines), lines)
+	}
+
+	for _, tc := range []struct {
+		text string
+		want bool
+	}{
+		{"env=old", false},
+		{"app=api", false},
+		{"app=worker", true},
+		{"version=1.0", true},
+	} {
+		if got := strings.Contains(lines[0], tc.text); got != tc.want {
+			t.Errorf("line %q contains %s: %v, want %v", lines[0], tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestReloadRestartsSchedule(t *testing.T) {
+	scheduled := LoggerConfig{LogType: "membuf", Severity: Error,
+		Schedule: []SeverityWindow{{From: "00:00", To: "23:59", Severity: Debug}}}
+
+	l := &Log{}
+	if err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{scheduled}, TimeZone: "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.mu.RLock()
+	stop := l.stop
+	l.mu.RUnlock()
+
+	if err := l.Reload(LogConfig{Loggers: []LoggerConfig{scheduled}, TimeZone: "Europe/Prague"}); err != nil {
+		t.Fatal(err)
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.stop == nil || l.stop == stop {
+		t.Error("schedule was not restarted with the reloaded time zone")
+	}
+}
+
+func TestReloadConcurrentWrites(t *testing.T) {
+	dir := tempDir(t)
+	cfg := func(name string) LogConfig {
+		return LogConfig{
+			Loggers:       []LoggerConfig{{LogType: "file", Severity: Information, Path: dir + "/" + name}},
+			DefaultFields: map[string]string{"config": name},
+			Levels:        map[string]LogSeverity{"db": Debug},
+		}
+	}
+
+	l := &Log{}
+	if err := l.SetupLoggers(cfg("a.log")); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				l.Infokv("message", "n", j)
+				l.Named("db").Debug("query")
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 20; j++ {
+			name := "a.log"
+			if j%2 == 0 {
+				name = "b.log"
+			}
+			if err := l.Reload(cfg(name)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestFailedReloadKeepsCurrentFile(t *testing.T) {
+	silenceInternalLog(t)
+
+	for _, rotate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rotate %v", rotate), func(t *testing.T) {
+			dir := tempDir(t)
+			file := LoggerConfig{LogType: "file", Severity: Information, Path: dir + "/app.log", Rotate: rotate}
+			l := newTestLog(t, file)
+			l.Info("before reload")
+
+			err := l.Reload(LogConfig{Loggers: []LoggerConfig{file, {LogType: "network", Severity: Information, Protocol: "tcp", Address: "127.0.0.1:1"}}})
+			if err == nil {
+				t.Fatal("reload with unreachable collector succeeded")
+			}
+			l.Info("after failed reload")
+
+			b, err := ioutil.ReadFile(dir + "/app.log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.IndexByte(b, 0) >= 0 {
+				t.Errorf("log file got NUL bytes: %q", b)
+			}
+			lines := readLines(t, dir+"/app.log")
+			if len(lines) != 2 || !strings.Contains(lines[0], "before reload") || !strings.Contains(lines[1], "after failed reload") {
+				t.Errorf("log file got %q", lines)
+			}
+			if entries, _ := ioutil.ReadDir(dir); len(entries) != 1 {
+				t.Errorf("log directory got %d files, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestReloadReopensKeptFile(t *testing.T) {
+	dir := tempDir(t)
+	file := LoggerConfig{LogType: "file", Severity: Information, Path: dir + "/app.log"}
+	l := newTestLog(t, file)
+	l.Info("before reload")
+
+	if err := l.Reload(LogConfig{Loggers: []LoggerConfig{file, {LogType: "membuf", Severity: Information}}}); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("after reload")
+
+	if lines := readLines(t, dir+"/app.log"); len(lines) != 1 || !strings.Contains(lines[0], "after reload") {
+		t.Errorf("log file got %q", lines)
+	}
+}
+
+func TestSetPathAfterReload(t *testing.T) {
+	dir := tempDir(t)
+	l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: dir + "/a.log"})
+	if err := l.Reload(LogConfig{Loggers: []LoggerConfig{{LogType: "file", Severity: Information, Path: dir + "/b.log"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(dir + "/a.log"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.SetPath(0, dir+"/a.log"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir + "/a.log"); err != nil {
+		t.Errorf("SetPath did not create the file: %v", err)
+	}
+}
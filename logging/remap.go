@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// severityRemap changes severity from to severity to for messages starting with prefix
+type severityRemap struct {
+	from   LogSeverity
+	to     LogSeverity
+	prefix string
+}
+
+// RemapSeverity makes messages of severity from starting with prefix (any message when
+// prefix is empty) written with severity to, e.g. to downgrade errors of a noisy library.
+// Remapping is applied before severity of the loggers and filters are checked, the first
+// matching rule wins and remapped messages are counted in Stats.
+func (l *Log) RemapSeverity(from, to LogSeverity, prefix string) error {
+	if !validSeverity(from) {
+		return fmt.Errorf("%d is invalid severity", from)
+	}
+	if !validSeverity(to) {
+		return fmt.Errorf("%d is invalid severity", to)
+	}
+
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.remaps = append(l.remaps, severityRemap{from: from, to: to, prefix: prefix})
+
+	return nil
+}
+
+// remap returns severity the message is written with, msg is called only when
+// a rule for the severity requires matching the message
+func (l *Log) remap(severity LogSeverity, msg func() string) LogSeverity {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, r := range l.remaps {
+		if r.from != severity {
+			continue
+		}
+		if r.prefix == "" || strings.HasPrefix(msg(), r.prefix) {
+			atomic.AddUint64(&l.counters.remapped, 1)
+			return r.to
+		}
+	}
+
+	return severity
+}
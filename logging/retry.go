@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultCreateBackoff = 100 * time.Millisecond
+
+// osCreate and osMkdirAll create log files and directories, they are replaced by tests
+var (
+	osCreate   = os.Create
+	osMkdirAll = os.MkdirAll
+)
+
+// validRetries checks retry settings of log file creation
+func validRetries(item LoggerConfig) error {
+	if item.CreateRetries < 0 {
+		return fmt.Errorf("%d is invalid number of create retries", item.CreateRetries)
+	}
+
+	if item.CreateBackoff != "" {
+		if d, err := time.ParseDuration(item.CreateBackoff); err != nil || d < 0 {
+			return fmt.Errorf("%s is invalid create backoff", item.CreateBackoff)
+		}
+	}
+
+	return nil
+}
+
+// retryCreate calls create until it succeeds or CreateRetries retries fail, waiting
+// CreateBackoff doubled after each attempt. Error of the first attempt is returned
+// when all of them fail.
+func retryCreate(item LoggerConfig, create func() error) error {
+	err := create()
+	if err == nil || item.CreateRetries == 0 {
+		return err
+	}
+
+	delay := defaultCreateBackoff
+	if item.CreateBackoff != "" {
+		delay, _ = time.ParseDuration(item.CreateBackoff)
+	}
+
+	for i := 0; i < item.CreateRetries; i++ {
+		time.Sleep(delay)
+		delay *= 2
+
+		if create() == nil {
+			return nil
+		}
+	}
+
+	return err
+}
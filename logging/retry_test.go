@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+var errTransient = errors.New("transient failure")
+
+// failCreate makes the first n file creations fail until the test finishes, it returns
+// pointer to the number of attempts
+func failCreate(t *testing.T, n int) *int {
+	attempts := 0
+	prev := osCreate
+	osCreate = func(name string) (*os.File, error) {
+		attempts++
+		if attempts <= n {
+			return nil, errTransient
+		}
+		return prev(name)
+	}
+	t.Cleanup(func() { osCreate = prev })
+
+	return &attempts
+}
+
+// failMkdirAll makes the first n directory creations fail until the test finishes
+func failMkdirAll(t *testing.T, n int) *int {
+	attempts := 0
+	prev := osMkdirAll
+	osMkdirAll = func(path string, perm os.FileMode) error {
+		attempts++
+		if attempts <= n {
+			return errTransient
+		}
+		return prev(path, perm)
+	}
+	t.Cleanup(func() { osMkdirAll = prev })
+
+	return &attempts
+}
+
+func TestCreateRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		fail     func(t *testing.T, n int) *int
+		failures int
+		retries  int
+		ok       bool
+		attempts int
+	}{
+		{"file created first time", failCreate, 0, 2, true, 1},
+		{"file created on retry", failCreate, 2, 2, true, 3},
+		{"file retries exhausted", failCreate, 3, 2, false, 3},
+		{"file without retries", failCreate, 1, 0, false, 1},
+		{"directory created on retry", failMkdirAll, 1, 1, true, 2},
+		{"directory retries exhausted", failMkdirAll, 2, 1, false, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			silenceInternalLog(t)
+			dir := tempDir(t)
+			attempts := tc.fail(t, tc.failures)
+
+			l := &Log{}
+			err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{{LogType: "file", Severity: Information,
+				Path: dir + "/logs/app.log", CreateRetries: tc.retries, CreateBackoff: "1ms"}}})
+			if err == nil {
+				l.Close()
+			}
+
+			if ok := err == nil; ok != tc.ok {
+				t.Errorf("setup error %v, want success %v", err, tc.ok)
+			}
+			if err != nil && !strings.Contains(err.Error(), errTransient.Error()) {
+				t.Errorf("setup error %v does not report the failure", err)
+			}
+			if *attempts != tc.attempts {
+				t.Errorf("made %d attempts, want %d", *attempts, tc.attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// defaultRotateSuffix is the time layout of the suffix appended to rotated log files
+const defaultRotateSuffix = "20060102150405"
+
+// rotation specifies whether existing log file is renamed before a new one is created
+// and how the renamed file is named
+type rotation struct {
+	enabled bool
+	suffix  string
+	dir     string
+	// marker renders first line of the new file, it is nil when marker is not written
+	marker func(t time.Time, previous string) string
+	// maxBytes is size the file is rotated at while it is written, zero is unlimited
+	maxBytes int64
+}
+
+func newRotation(item LoggerConfig) rotation {
+	r := rotation{enabled: item.Rotate, suffix: item.RotateSuffixFormat, dir: item.RotateDirFormat,
+		maxBytes: item.MaxFileBytes}
+	if r.suffix == "" {
+		r.suffix = defaultRotateSuffix
+	}
+	if item.RotateMarker {
+		r.marker = rotateMarker(item)
+	}
+
+	return r
+}
+
+// rotateMarker returns function rendering rotation marker in format of the logger,
+// it returns nil for syslog format whose frames the marker would break
+func rotateMarker(item LoggerConfig) func(t time.Time, previous string) string {
+	format := strings.ToLower(item.Format)
+	if format == FormatSyslog {
+		return nil
+	}
+
+	if format != FormatJSON && strings.ToLower(item.LogType) != "dual" {
+		return func(t time.Time, previous string) string {
+			return fmt.Sprintf("=== log rotated at %s, previous: %s ===\n", t.Format(textTimeLayout), previous)
+		}
+	}
+
+	lg := &Logger{severityEncoding: strings.ToLower(item.SeverityEncoding), prefix: item.Prefix}
+	lg.keys, _ = resolveFieldKeys(item.FieldKeys, lg.severityEncoding, lg.prefix)
+
+	return func(t time.Time, previous string) string {
+		return lg.encodeJSON(t, Information, "log rotated", map[string]interface{}{"previous": previous}) + "\n"
+	}
+}
+
+// validRotateSuffix checks the suffix layout produces names safe on common file systems
+func validRotateSuffix(layout string) error {
+	if layout == "" {
+		return nil
+	}
+
+	suffix := time.Date(2001, 2, 3, 4, 5, 6, 7, time.UTC).Format(layout)
+	if suffix == layout || strings.ContainsAny(suffix, "/\\:*?\"<>| \t\n") {
+		return fmt.Errorf("%s is invalid rotate suffix format", layout)
+	}
+
+	return nil
+}
+
+// validRotateDir checks the directory layout produces relative paths safe on common file systems
+func validRotateDir(layout string) error {
+	if layout == "" {
+		return nil
+	}
+
+	dir := time.Date(2001, 2, 3, 4, 5, 6, 7, time.UTC).Format(layout)
+	if dir == layout || path.IsAbs(dir) || strings.Contains(dir, "..") ||
+		strings.ContainsAny(dir, "\\:*?\"<>| \t\n") {
+		return fmt.Errorf("%s is invalid rotate directory format", layout)
+	}
+
+	return nil
+}
+
+// archivePath returns path the log file is renamed to when it is rotated at t, rotated files
+// are put into subdirectory of the log file directory when the directory layout is set
+func (r rotation) archivePath(logFilePath string, t time.Time) string {
+	if r.dir != "" {
+		logFilePath = path.Join(path.Dir(logFilePath), t.Format(r.dir), path.Base(logFilePath))
+	}
+
+	return fmt.Sprintf("%s.%s", logFilePath, t.Format(r.suffix))
+}
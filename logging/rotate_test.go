@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestRotateMarker(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format string
+		json   bool
+	}{
+		{"text", FormatText, false},
+		{"json", FormatJSON, true},
+		{"auto resolved to json", FormatAuto, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			unsetEnv(t, autoFormatEnv)
+			dir := tempDir(t)
+			if err := ioutil.WriteFile(dir+"/app.log", []byte("previous\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: dir + "/app.log",
+				Format: tc.format, Rotate: true, RotateMarker: true})
+			l.Info("first")
+			l.Close()
+
+			lines := readLines(t, dir+"/app.log")
+			if len(lines) != 2 {
+				t.Fatalf("got lines %q, want marker and message", lines)
+			}
+
+			if !tc.json {
+				if !strings.HasPrefix(lines[0], "=== log rotated at ") || !strings.Contains(lines[0], "previous: "+dir+"/app.log.") {
+					t.Errorf("marker %q is not text marker", lines[0])
+				}
+				return
+			}
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+				t.Fatalf("marker %q is not JSON: %v", lines[0], err)
+			}
+			previous, _ := entry["previous"].(string)
+			if entry["msg"] != "log rotated" || !strings.HasPrefix(previous, dir+"/app.log.") {
+				t.Errorf("marker %v misses message or previous file", entry)
+			}
+		})
+	}
+}
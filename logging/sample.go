@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// validHeavySampling checks thresholds and rate of sampling entries with many fields
+func validHeavySampling(item LoggerConfig) error {
+	if item.SampleMinFields < 0 {
+		return fmt.Errorf("%d is invalid number of fields to sample", item.SampleMinFields)
+	}
+	if item.SampleMinBytes < 0 {
+		return fmt.Errorf("%d is invalid size of fields to sample", item.SampleMinBytes)
+	}
+	if item.SampleEvery < 0 {
+		return fmt.Errorf("%d is invalid sample rate", item.SampleEvery)
+	}
+
+	return nil
+}
+
+// heavy reports whether entry with the fields reaches either of the sampling thresholds,
+// size of the fields is estimated by their text rendering
+func (l *Logger) heavy(fields map[string]interface{}, fieldsText string) bool {
+	if l.config.SampleMinFields > 0 && len(fields) >= l.config.SampleMinFields {
+		return true
+	}
+
+	return l.config.SampleMinBytes > 0 && len(fieldsText) >= l.config.SampleMinBytes
+}
+
+// sampledOut reports whether the entry is dropped by the logger, only every SampleEvery-th
+// heavy entry is kept while the others always pass
+func (l *Logger) sampledOut(fields map[string]interface{}, fieldsText string) bool {
+	if l.config.SampleEvery <= 1 || !l.heavy(fields, fieldsText) {
+		return false
+	}
+
+	return atomic.AddUint32(&l.heavySeen, 1)%uint32(l.config.SampleEvery) != 1
+}
+
+// defaultSampleKeys limits number of keys tracked by keyed sampling when SampleKeys is not set
+const defaultSampleKeys = 1000
+
+// keyedSampler keeps every n-th entry for each value of the key field, counters of the least
+// recently seen values are evicted once max values are tracked
+type keyedSampler struct {
+	mu     sync.Mutex
+	field  string
+	every  uint64
+	max    int
+	counts map[string]*list.Element
+	lru    *list.List
+}
+
+type keyCount struct {
+	key   string
+	count uint64
+}
+
+func newKeyedSampler(item LoggerConfig) (*keyedSampler, error) {
+	if item.SampleKeyField == "" {
+		return nil, nil
+	}
+	if item.SampleKeyEvery < 1 {
+		return nil, fmt.Errorf("%d is invalid sample rate of key %s", item.SampleKeyEvery, item.SampleKeyField)
+	}
+	if item.SampleKeys < 0 {
+		return nil, fmt.Errorf("%d is invalid number of sampled keys", item.SampleKeys)
+	}
+
+	max := item.SampleKeys
+	if max == 0 {
+		max = defaultSampleKeys
+	}
+
+	return &keyedSampler{
+		field:  item.SampleKeyField,
+		every:  uint64(item.SampleKeyEvery),
+		max:    max,
+		counts: make(map[string]*list.Element),
+		lru:    list.New(),
+	}, nil
+}
+
+// drop reports whether the entry is dropped, entries without the key field always pass
+func (s *keyedSampler) drop(fields map[string]interface{}) bool {
+	v, ok := fields[s.field]
+	if !ok || s.every == 1 {
+		return false
+	}
+	key := fmt.Sprintf("%v", v)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.counts[key]
+	if ok {
+		s.lru.MoveToFront(el)
+	} else {
+		if s.lru.Len() >= s.max {
+			oldest := s.lru.Back()
+			s.lru.Remove(oldest)
+			delete(s.counts, oldest.Value.(*keyCount).key)
+		}
+		el = s.lru.PushFront(&keyCount{key: key})
+		s.counts[key] = el
+	}
+
+	kc := el.Value.(*keyCount)
+	kc.count++
+
+	return kc.count%s.every != 1
+}
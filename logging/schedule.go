@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// SeverityWindow specifies severity used by the logger within daily time window.
+// From and To are given as HH:MM, From is inclusive and To exclusive. Window may span
+// midnight (e.g. 22:00 - 02:00).
+type SeverityWindow struct {
+	From     string      `json:"from" yaml:"from"`
+	To       string      `json:"to" yaml:"to"`
+	Severity LogSeverity `json:"severity" yaml:"severity"`
+}
+
+type severityWindow struct {
+	from     int
+	to       int
+	severity LogSeverity
+}
+
+func (w severityWindow) contains(minute int) bool {
+	if w.from <= w.to {
+		return minute >= w.from && minute < w.to
+	}
+
+	return minute >= w.from || minute < w.to
+}
+
+func parseDayMinute(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%s is invalid time of day", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseSchedule(windows []SeverityWindow) ([]severityWindow, error) {
+	var schedule []severityWindow
+	for _, w := range windows {
+		from, err := parseDayMinute(w.From)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parseDayMinute(w.To)
+		if err != nil {
+			return nil, err
+		}
+
+		if !validSeverity(w.Severity) {
+			return nil, fmt.Errorf("%d is invalid severity", w.Severity)
+		}
+
+		schedule = append(schedule, severityWindow{from: from, to: to, severity: w.Severity})
+	}
+
+	return schedule, nil
+}
+
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is invalid time zone", name)
+	}
+
+	return loc, nil
+}
+
+// scheduledSeverity returns severity the logger should use at the given time
+func (l *Logger) scheduledSeverity(t time.Time) LogSeverity {
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range l.schedule {
+		if w.contains(minute) {
+			return w.severity
+		}
+	}
+
+	return l.baseSeverity
+}
+
+func (l *Log) applySchedule(t time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, lg := range l.loggers {
+		if len(lg.schedule) == 0 {
+			continue
+		}
+
+		severity := lg.scheduledSeverity(t)
+		if _, ok := l.boostPrior[lg]; ok {
+			// scheduled severity is restored once the boost expires
+			l.boostPrior[lg] = severity
+			if severity < l.boostSeverity {
+				severity = l.boostSeverity
+			}
+		}
+		lg.severity = severity
+	}
+}
+
+// startSchedule starts goroutine adjusting severities of scheduled loggers at the start
+// of every minute. Must be called with l.mu locked.
+func (l *Log) startSchedule(loc *time.Location) {
+	scheduled := false
+	for _, lg := range l.loggers {
+		if len(lg.schedule) > 0 {
+			lg.severity = lg.scheduledSeverity(time.Now().In(loc))
+			scheduled = true
+		}
+	}
+
+	if !scheduled || l.stop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	l.stop = stop
+
+	go func() {
+		for {
+			now := time.Now()
+			timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case t := <-timer.C:
+				l.applySchedule(t.In(loc))
+			}
+		}
+	}()
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"os"
+	"sync"
+)
+
+// screenWriter writes into standard output or standard error resolved when the logger
+// is set up and again on Reopen
+type screenWriter struct {
+	mu      sync.RWMutex
+	resolve func() *os.File
+	out     *os.File
+}
+
+func newScreenWriter(resolve func() *os.File) *screenWriter {
+	return &screenWriter{resolve: resolve, out: resolve()}
+}
+
+func stdout() *os.File {
+	return os.Stdout
+}
+
+func stderr() *os.File {
+	return os.Stderr
+}
+
+// Write implements io.Writer interface
+func (w *screenWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.out.Write(p)
+}
+
+func (w *screenWriter) reopen() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.out = w.resolve()
+}
+
+// Reopen makes screen, stderr and console loggers write into the current os.Stdout and os.Stderr.
+// This matters when the process replaces its standard streams after the log was set up,
+// e.g. when a daemon supervisor redirects them into a file which is later rotated.
+func (l *Log) Reopen() error {
+	l = l.base()
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, lg := range l.loggers {
+		if lg.screen != nil {
+			lg.screen.reopen()
+		}
+		if lg.errScreen != nil {
+			lg.errScreen.reopen()
+		}
+	}
+
+	return nil
+}
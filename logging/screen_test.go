@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReopenFollowsRedirection(t *testing.T) {
+	for _, logType := range []string{"screen", "stderr"} {
+		t.Run(logType, func(t *testing.T) {
+			oldOut, oldErr := redirectStd(t)
+			l := newTestLog(t, LoggerConfig{LogType: logType, Severity: Information})
+			l.Info("before reopen")
+
+			newOut, newErr := redirectStd(t)
+			l.Info("stale stream")
+			if err := l.Reopen(); err != nil {
+				t.Fatal(err)
+			}
+			l.Info("after reopen")
+
+			oldPath, newPath := oldOut, newOut
+			if logType == "stderr" {
+				oldPath, newPath = oldErr, newErr
+			}
+			old := strings.Join(readLines(t, oldPath), "\n")
+			if !strings.Contains(old, "before reopen") || !strings.Contains(old, "stale stream") || strings.Contains(old, "after reopen") {
+				t.Errorf("previous stream got %q", old)
+			}
+			current := strings.Join(readLines(t, newPath), "\n")
+			if !strings.Contains(current, "after reopen") || strings.Contains(current, "before reopen") {
+				t.Errorf("current stream got %q", current)
+			}
+		})
+	}
+}
+
+func TestConsoleSplitsBySeverity(t *testing.T) {
+	outPath, errPath := redirectStd(t)
+	l := newTestLog(t, LoggerConfig{LogType: "console", Severity: Verbose})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				l.Errorf("error %d-%d", g, i)
+				l.Warningf("warning %d-%d", g, i)
+				l.Infof("info %d-%d", g, i)
+				l.Debugf("debug %d-%d", g, i)
+				l.Verbosef("verbose %d-%d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	l.Log(Fatal, "fatal")
+
+	for _, tc := range []struct {
+		path          string
+		want, notWant []string
+	}{
+		{outPath, []string{"INFO", "DEBUG", "VERBOSE"}, []string{"FATAL", "ERROR", "WARNING"}},
+		{errPath, []string{"FATAL", "ERROR", "WARNING"}, []string{"INFO", "DEBUG", "VERBOSE"}},
+	} {
+		lines := readLines(t, tc.path)
+		count := map[string]int{}
+		for _, line := range lines {
+			for _, s := range append(tc.want, tc.notWant...) {
+				if strings.Contains(line, s) {
+					count[s]++
+				}
+			}
+		}
+		for _, s := range tc.notWant {
+			if count[s] != 0 {
+				t.Errorf("%s got %d %s messages", tc.path, count[s], s)
+			}
+		}
+		for _, s := range tc.want {
+			want := 100
+			if s == "FATAL" {
+				want = 1
+			}
+			if count[s] != want {
+				t.Errorf("%s got %d %s messages, want %d", tc.path, count[s], s, want)
+			}
+		}
+	}
+}
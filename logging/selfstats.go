@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// parseSelfStatsInterval validates SelfStatsInterval, empty interval disables the summary
+func parseSelfStatsInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s is invalid self stats interval", s)
+	}
+
+	return d, nil
+}
+
+// startSelfStats starts goroutine writing summary of logging statistics every interval,
+// it runs until Close. Must be called with l.mu locked.
+func (l *Log) startSelfStats(interval time.Duration) {
+	if interval <= 0 || l.selfStats != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	l.selfStats = stop
+	prev, prevTime := l.Stats(), time.Now()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				cur := l.Stats()
+				l.WithFields(selfStatsFields(prev, cur, now.Sub(prevTime))).Info("logging stats")
+				prev, prevTime = cur, now
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// selfStatsFields returns message rates per severity since the previous summary and current
+// values of all other counters of Stats named in snake case (e.g. dropped_newest), so the
+// summary includes counters added to Stats later
+func selfStatsFields(prev, cur Stats, elapsed time.Duration) map[string]interface{} {
+	rates := make(map[string]interface{}, len(cur.Messages))
+	for s, n := range cur.Messages {
+		rate := float64(n-prev.Messages[s]) / elapsed.Seconds()
+		rates[getSeverityName(s)] = math.Round(rate*100) / 100
+	}
+
+	fields := map[string]interface{}{"messages_per_sec": rates}
+	v := reflect.ValueOf(cur)
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Uint64 {
+			fields[snakeCase(v.Type().Field(i).Name)] = f.Uint()
+		}
+	}
+
+	return fields
+}
+
+// snakeCase converts Go identifier to lower case words separated by underscores
+func snakeCase(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
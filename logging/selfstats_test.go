@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelfStatsFields(t *testing.T) {
+	prev := Stats{Messages: map[LogSeverity]uint64{Information: 10, Error: 0}}
+	cur := Stats{
+		Filtered:        1,
+		DroppedNewest:   2,
+		DroppedOldest:   3,
+		DroppedTimeout:  4,
+		Remapped:        5,
+		Escalated:       6,
+		SampledOut:      7,
+		SampledByKey:    8,
+		DroppedDiskFull: 9,
+		DroppedBacklog:  10,
+		EncodeErrors:    11,
+		Bytes:           12,
+		Messages:        map[LogSeverity]uint64{Information: 30, Error: 1},
+	}
+
+	want := map[string]interface{}{
+		"messages_per_sec":  map[string]interface{}{"INFO": 10.0, "ERROR": 0.5},
+		"filtered":          uint64(1),
+		"dropped_newest":    uint64(2),
+		"dropped_oldest":    uint64(3),
+		"dropped_timeout":   uint64(4),
+		"remapped":          uint64(5),
+		"escalated":         uint64(6),
+		"sampled_out":       uint64(7),
+		"sampled_by_key":    uint64(8),
+		"dropped_disk_full": uint64(9),
+		"dropped_backlog":   uint64(10),
+		"encode_errors":     uint64(11),
+		"bytes":             uint64(12),
+	}
+	if got := selfStatsFields(prev, cur, 2*time.Second); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSelfStatsSummary(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers:           []LoggerConfig{{LogType: "membuf", Severity: Information}},
+		SelfStatsInterval: "20ms",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.Info("message")
+
+	var summary string
+	for deadline := time.Now().Add(2 * time.Second); summary == "" && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		for _, line := range exportLines(l) {
+			if strings.Contains(line, "logging stats") {
+				summary = line
+			}
+		}
+	}
+	if summary == "" {
+		t.Fatal("summary was not written")
+	}
+	for _, key := range []string{"messages_per_sec.INFO=", "sampled_by_key=", "dropped_disk_full=", "encode_errors="} {
+		if !strings.Contains(summary, key) {
+			t.Errorf("summary %q does not contain %s", summary, key)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import "fmt"
+
+// SetPath points file logger with the given index at new path keeping its other settings.
+// New file is created first (including its directory), the old one is closed only after
+// the logger has been switched, so the logger keeps writing into the old file on failure.
+func (l *Log) SetPath(index int, path string) error {
+	l = l.base()
+	if l == nil {
+		return fmt.Errorf("logger %d does not exist", index)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if index < 0 || index >= len(l.loggers) {
+		return fmt.Errorf("logger %d does not exist", index)
+	}
+
+	lg := l.loggers[index]
+	if lg.logType != File && lg.logType != Dual {
+		return fmt.Errorf("logger %d is not file logger", index)
+	}
+
+	if path == "" {
+		return fmt.Errorf("log file path is not set")
+	}
+
+	item := lg.config
+	item.Path = path
+
+	// async writer of the old file is closed with it, newWriter adds the one of the new file
+	prior := lg.async
+	lg.async = nil
+	w, c, err := l.newWriter(lg, item)
+	if err != nil {
+		lg.async = prior
+		return err
+	}
+
+	old := lg.out
+	lg.rawLogger.SetOutput(w)
+	lg.out = c
+	lg.config = item
+
+	if old != nil {
+		if err := old.Close(); err != nil {
+			return fmt.Errorf("failed to close previous log file: %s", err.Error())
+		}
+	}
+
+	return nil
+}
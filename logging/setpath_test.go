@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetPathAsync(t *testing.T) {
+	dir := tempDir(t)
+	l := newTestLog(t, LoggerConfig{LogType: "file", Severity: Information, Path: filepath.Join(dir, "a.log"), Async: true})
+	l.Info("first")
+
+	for _, name := range []string{"b.log", "c.log"} {
+		if err := l.SetPath(0, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+		l.Info(name)
+	}
+	if err := l.SetPath(0, filepath.Join(dir, "missing", "\x00.log")); err == nil {
+		t.Fatal("invalid path accepted")
+	}
+	if n := len(l.loggers[0].async); n != 1 {
+		t.Errorf("logger keeps %d async writers, want 1", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.WaitIdle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.log", "b.log", "c.log"} {
+		if n := len(readLines(t, filepath.Join(dir, name))); n != 1 {
+			t.Errorf("%s holds %d lines, want 1", name, n)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package logging
+
+import "testing"
+
+func TestValidSeverity(t *testing.T) {
+	for _, tc := range []struct {
+		severity LogSeverity
+		valid    bool
+	}{
+		{0, false},
+		{5, false},
+		{Fatal, true},
+		{15, false},
+		{Warning, true},
+		{Verbose, true},
+		{65, false},
+		{70, false},
+		{1000, false},
+	} {
+		if got := validSeverity(tc.severity); got != tc.valid {
+			t.Errorf("validSeverity(%d) = %v, want %v", tc.severity, got, tc.valid)
+		}
+	}
+}
+
+func TestLogRejectsInvalidSeverity(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Verbose})
+
+	for _, severity := range []LogSeverity{0, 15, 65, 1000} {
+		if err := l.Log(severity, "message"); err == nil {
+			t.Errorf("Log accepted severity %d", severity)
+		}
+		if err := l.Logf(severity, "message %d", 1); err == nil {
+			t.Errorf("Logf accepted severity %d", severity)
+		}
+		if n, err := l.LogBytes(severity, "message"); err == nil || n != 0 {
+			t.Errorf("LogBytes returned %d, %v for severity %d", n, err, severity)
+		}
+	}
+	if lines := exportLines(l); len(lines) != 0 {
+		t.Errorf("invalid severities wrote %q", lines)
+	}
+
+	if err := l.Log(Error, "valid"); err != nil {
+		t.Errorf("Log rejected valid severity: %s", err)
+	}
+	if n, err := l.LogBytes(Information, "valid"); err != nil || n == 0 {
+		t.Errorf("LogBytes returned %d, %v for valid severity", n, err)
+	}
+}
+
+func TestSetupRejectsInvalidSeverity(t *testing.T) {
+	for _, severity := range []LogSeverity{0, 15, 65, 1000} {
+		l := &Log{}
+		if err := l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{{LogType: "membuf", Severity: severity}}}); err == nil {
+			l.Close()
+			t.Errorf("setup accepted severity %d", severity)
+		}
+	}
+}
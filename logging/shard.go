@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// Sharding strategies of sharded logger
+const (
+	// ShardRoundRobin writes messages into shards in turn
+	ShardRoundRobin = "round-robin"
+	// ShardHash writes messages into shard chosen by hash of the shard field value
+	ShardHash = "hash"
+)
+
+// multiCloser closes all its closers
+type multiCloser []io.Closer
+
+// Close implements io.Closer interface
+func (m multiCloser) Close() error {
+	var errs []string
+	for _, c := range m {
+		if c == nil {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// setupShards creates file logger for each of the configured paths
+func (l *Log) setupShards(lg *Logger, item LoggerConfig) error {
+	lg.config.ShardBy = strings.ToLower(item.ShardBy)
+	switch lg.config.ShardBy {
+	case "", ShardRoundRobin:
+	case ShardHash:
+		if item.ShardField == "" {
+			return fmt.Errorf("shard field is not set")
+		}
+	default:
+		return fmt.Errorf("%s is invalid sharding strategy", item.ShardBy)
+	}
+
+	if len(item.Paths) == 0 {
+		return fmt.Errorf("paths of sharded logger are not set")
+	}
+
+	shard := &Logger{logType: File, format: lg.format, dryRun: lg.dryRun, enqueueTimeout: lg.enqueueTimeout, held: lg.held}
+	var closers multiCloser
+	for _, p := range item.Paths {
+		shardItem := item
+		shardItem.Path = p
+
+		shard.sync = nil
+		w, c, err := l.newWriter(shard, shardItem)
+		if err != nil {
+			closers.Close()
+			return err
+		}
+
+		closers = append(closers, c)
+		lg.shards = append(lg.shards, lg.newRawLogger(w))
+		lg.shardSyncs = append(lg.shardSyncs, shard.sync)
+	}
+
+	lg.async = shard.async
+	lg.deferred = shard.deferred
+	lg.rawLogger = lg.shards[0]
+	lg.out = closers
+
+	return nil
+}
+
+// shard returns raw logger of the shard the message with given fields is written into and
+// syncer of its file (nil when the file is not synced directly, e.g. async shard)
+func (l *Logger) shard(fields map[string]interface{}) (*log.Logger, syncer) {
+	var i uint32
+	if l.config.ShardBy == ShardHash {
+		h := fnv.New32a()
+		if v, ok := fields[l.config.ShardField]; ok {
+			fmt.Fprintf(h, "%v", v)
+		}
+		i = h.Sum32() % uint32(len(l.shards))
+	} else {
+		i = (atomic.AddUint32(&l.shardNext, 1) - 1) % uint32(len(l.shards))
+	}
+
+	return l.shards[i], l.shardSyncs[i]
+}
@@ -0,0 +1,104 @@
+package logging
+
+import "log"
+
+// Snapshot returns independent copy of the log capturing current loggers' severities,
+// prefixes, fields, filters, event handlers, severity remapping and escalation rules and
+// log wide settings (levels, sequence numbers, caller skip, ...). The copy has its own
+// statistics, sequence numbers, tail buffer, repeat counts of escalation rules and counters of
+// keyed sampling (all empty at first).
+// The copy writes into the same targets but its settings can be changed without affecting
+// the parent. Log is safe for concurrent use, so both parent and snapshot may be handed to
+// other goroutines. Closing the snapshot does not close targets owned by the parent.
+func (l *Log) Snapshot() *Log {
+	b := l.base()
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	s := &Log{
+		loggers:     make([]*Logger, 0, len(b.loggers)),
+		filters:     append([]Filter(nil), b.filters...),
+		handlers:    append(make([]func(Event), 0, len(b.handlers)), b.handlers...),
+		remaps:      append([]severityRemap(nil), b.remaps...),
+		escalations: make([]*escalation, 0, len(b.escalations)),
+		levels:      b.levels,
+		colors:      b.colors,
+		name:        l.name,
+		callerSkip:  l.callerSkip,
+		includeSeq:  b.includeSeq,
+		includeGID:  b.includeGID,
+		redactor:    b.redactor,
+		trace:       b.trace,
+
+		recordTemplate: b.recordTemplate,
+	}
+	for _, e := range b.escalations {
+		s.escalations = append(s.escalations, e.clone())
+	}
+	if b.tail != nil {
+		s.tail = newTailBuffer(len(b.tail.events))
+	}
+	fields, _ := l.entryFields()
+	s.order = l.fieldOrder()
+	s.setFields(mergeFields(fields, nil))
+
+	for _, lg := range b.loggers {
+		c := lg.clone()
+		c.stats = &s.counters
+		s.loggers = append(s.loggers, c)
+	}
+
+	return s
+}
+
+// clone copies settings of the logger writing into the same targets. Fields updated atomically
+// while messages are written (shardNext, heavySeen) are not read, the copy starts them and
+// counters of keyed sampling from zero.
+// The copy owns neither the schedule nor the targets, so it must not close them.
+func (lg *Logger) clone() *Logger {
+	c := &Logger{
+		rawLogger:        log.New(lg.rawLogger.Writer(), lg.rawLogger.Prefix(), lg.rawLogger.Flags()),
+		severity:         lg.severity,
+		baseSeverity:     lg.baseSeverity,
+		logType:          lg.logType,
+		prefix:           lg.prefix,
+		screen:           lg.screen,
+		errScreen:        lg.errScreen,
+		dual:             lg.dual,
+		config:           lg.config,
+		shards:           lg.shards,
+		shardSyncs:       lg.shardSyncs,
+		syslog:           lg.syslog,
+		keys:             lg.keys,
+		format:           lg.format,
+		severityEncoding: lg.severityEncoding,
+		severityStyle:    lg.severityStyle,
+		dryRun:           lg.dryRun,
+		enqueueTimeout:   lg.enqueueTimeout,
+		separator:        lg.separator,
+		sync:             lg.sync,
+		async:            lg.async,
+		membuf:           lg.membuf,
+		color:            lg.color,
+		colors:           lg.colors,
+		priority:         lg.priority,
+		encodeErrors:     lg.encodeErrors,
+		stats:            lg.stats,
+		newlines:         lg.newlines,
+		indent:           lg.indent,
+		ownTime:          lg.ownTime,
+	}
+	if lg.keyed != nil {
+		// the configuration was validated when the logger was set up
+		c.keyed, _ = newKeyedSampler(lg.config)
+	}
+	if lg.errLogger != nil {
+		c.errLogger = log.New(lg.errLogger.Writer(), lg.errLogger.Prefix(), lg.errLogger.Flags())
+	}
+
+	return c
+}
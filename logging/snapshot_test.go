@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotConcurrentWithSharding(t *testing.T) {
+	dir := tempDir(t)
+	l := newTestLog(t, LoggerConfig{
+		LogType:  "sharded",
+		Severity: Information,
+		Paths:    []string{dir + "/a.log", dir + "/b.log"},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l.Infokv("message", "n", j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l.Snapshot().Infokv("snapshot message", "n", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(readLines(t, dir+"/a.log")) + len(readLines(t, dir+"/b.log")); n != 1600 {
+		t.Errorf("shards hold %d lines, want 1600", n)
+	}
+}
+
+func TestSnapshotIsIndependent(t *testing.T) {
+	l := &Log{}
+	err := l.SetupLoggers(LogConfig{
+		Loggers:    []LoggerConfig{{LogType: "membuf", Severity: Information}},
+		IncludeSeq: true,
+		TailSize:   10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	parent := l.WithFields(map[string]interface{}{"request": "r1"})
+	parent.Info("parent")
+
+	s := parent.Snapshot()
+	s.SetVersion("2.0")
+	s.Info("snapshot")
+	s.Info("snapshot")
+	parent.Info("parent")
+
+	lines := exportLines(l)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	for i, tc := range []struct {
+		want    []string
+		notWant []string
+	}{
+		{[]string{"request=r1", "seq=1"}, []string{"version"}},
+		{[]string{"request=r1", "seq=1", "version=2.0"}, nil},
+		{[]string{"request=r1", "seq=2", "version=2.0"}, nil},
+		{[]string{"request=r1", "seq=2"}, []string{"version"}},
+	} {
+		for _, w := range tc.want {
+			if !strings.Contains(lines[i], w) {
+				t.Errorf("line %q does not contain %s", lines[i], w)
+			}
+		}
+		for _, w := range tc.notWant {
+			if strings.Contains(lines[i], w) {
+				t.Errorf("line %q contains %s", lines[i], w)
+			}
+		}
+	}
+
+	if n := l.Stats().Messages[Information]; n != 2 {
+		t.Errorf("parent counted %d messages, want 2", n)
+	}
+	if n := s.Stats().Messages[Information]; n != 2 {
+		t.Errorf("snapshot counted %d messages, want 2", n)
+	}
+	if n := len(l.DrainTail(10)); n != 2 {
+		t.Errorf("parent tail holds %d messages, want 2", n)
+	}
+	if n := len(s.DrainTail(10)); n != 2 {
+		t.Errorf("snapshot tail holds %d messages, want 2", n)
+	}
+}
+
+func TestSnapshotCountsIndependently(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, SampleKeyField: "user", SampleKeyEvery: 2})
+	if err := l.EscalateRepeated(Warning, 2, time.Minute, Error); err != nil {
+		t.Fatal(err)
+	}
+	l.Warning("disk low")
+	l.Warning("disk low")
+	l.Infokv("login", "user", "u1")
+
+	s := l.Snapshot()
+	s.Warning("disk low")
+	s.Infokv("login", "user", "u1")
+	l.Warning("disk low")
+
+	lines := exportLines(l)
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want 6: %q", len(lines), lines)
+	}
+	for i, want := range []string{"WARNING", "WARNING", "login", "WARNING", "login", "ERROR"} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %q does not contain %s", lines[i], want)
+		}
+	}
+}
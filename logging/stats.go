@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Stats provides logging statistics
+type Stats struct {
+	// Filtered is number of messages dropped by filters
+	Filtered uint64
+	// DroppedNewest is number of messages dropped by async loggers with drop-newest overflow policy
+	DroppedNewest uint64
+	// DroppedOldest is number of messages dropped by async loggers with drop-oldest overflow policy
+	DroppedOldest uint64
+	// DroppedTimeout is number of messages dropped by async loggers after waiting EnqueueTimeout
+	DroppedTimeout uint64
+	// Remapped is number of messages whose severity was changed by RemapSeverity
+	Remapped uint64
+	// Escalated is number of repeated messages whose severity was raised by EscalateRepeated
+	Escalated uint64
+	// SampledOut is number of entries with many fields dropped by loggers sampling them
+	SampledOut uint64
+	// SampledByKey is number of entries dropped by loggers sampling them by value of a field
+	SampledByKey uint64
+	// DroppedDiskFull is number of messages dropped by file loggers because the disk was full
+	DroppedDiskFull uint64
+	// DroppedBacklog is number of messages dropped by batching targets (database) whose backlog
+	// was full while writes failed
+	DroppedBacklog uint64
+	// EncodeErrors is number of field values which failed to encode as JSON
+	EncodeErrors uint64
+	// Messages is number of written messages per severity
+	Messages map[LogSeverity]uint64
+	// Bytes is number of bytes written (or only rendered in dry run) by all loggers
+	Bytes uint64
+}
+
+// countingWriter counts bytes written into the target, in dry run it only counts them
+type countingWriter struct {
+	out    io.Writer
+	bytes  *uint64
+	dryRun bool
+}
+
+// Write implements io.Writer interface
+func (w *countingWriter) Write(p []byte) (int, error) {
+	atomic.AddUint64(w.bytes, uint64(len(p)))
+	if w.dryRun {
+		return len(p), nil
+	}
+
+	return w.out.Write(p)
+}
+
+func (l *Log) countBytes(w io.Writer, dryRun bool) io.Writer {
+	return &countingWriter{out: w, bytes: &l.counters.bytes, dryRun: dryRun}
+}
+
+// counters are updated atomically, the struct must stay the first field of Log
+// to keep 64-bit alignment on 32-bit platforms
+type counters struct {
+	filtered        uint64
+	droppedNewest   uint64
+	droppedOldest   uint64
+	seq             uint64
+	bytes           uint64
+	messages        [6]uint64
+	droppedTimeout  uint64
+	remapped        uint64
+	escalated       uint64
+	sampledOut      uint64
+	encodeErrors    uint64
+	sampledByKey    uint64
+	droppedDiskFull uint64
+	droppedBacklog  uint64
+}
+
+// statsUser is implemented by targets counting dropped messages into statistics of the log
+type statsUser interface {
+	setStats(c *counters)
+}
+
+// Stats returns current logging statistics
+func (l *Log) Stats() Stats {
+	l = l.base()
+	if l == nil {
+		return Stats{}
+	}
+
+	messages := make(map[LogSeverity]uint64, len(l.counters.messages))
+	for i := range l.counters.messages {
+		messages[LogSeverity((i+1)*10)] = atomic.LoadUint64(&l.counters.messages[i])
+	}
+
+	return Stats{
+		Filtered:        atomic.LoadUint64(&l.counters.filtered),
+		DroppedNewest:   atomic.LoadUint64(&l.counters.droppedNewest),
+		DroppedOldest:   atomic.LoadUint64(&l.counters.droppedOldest),
+		DroppedTimeout:  atomic.LoadUint64(&l.counters.droppedTimeout),
+		Remapped:        atomic.LoadUint64(&l.counters.remapped),
+		Escalated:       atomic.LoadUint64(&l.counters.escalated),
+		SampledOut:      atomic.LoadUint64(&l.counters.sampledOut),
+		EncodeErrors:    atomic.LoadUint64(&l.counters.encodeErrors),
+		SampledByKey:    atomic.LoadUint64(&l.counters.sampledByKey),
+		DroppedDiskFull: atomic.LoadUint64(&l.counters.droppedDiskFull),
+		DroppedBacklog:  atomic.LoadUint64(&l.counters.droppedBacklog),
+		Messages:        messages,
+		Bytes:           atomic.LoadUint64(&l.counters.bytes),
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"log"
+	"strings"
+)
+
+// stdWriter writes output of the standard library logger into the log
+type stdWriter struct {
+	log      *Log
+	severity LogSeverity
+}
+
+// Write implements io.Writer interface
+func (w *stdWriter) Write(p []byte) (int, error) {
+	w.log.writeMessage(w.severity, strings.TrimRight(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// CaptureStandardLog redirects the global logger of the standard log package into the log
+// with the given severity. Note it changes process-wide state: output, flags (set to 0 to avoid
+// duplicate timestamps) and prefix of the standard logger are replaced until the returned
+// restore function is called.
+func (l *Log) CaptureStandardLog(severity LogSeverity) func() {
+	out, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+
+	log.SetOutput(&stdWriter{log: l, severity: severity})
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
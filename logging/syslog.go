@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatSyslog writes messages framed according to RFC 5424, over stream connections
+// (tcp, unix) the frames are prefixed with their length (octet counting, RFC 6587)
+const FormatSyslog = "syslog-rfc5424"
+
+// defaultFacility is the user-level messages facility
+const defaultFacility = 1
+
+// syslogSDID identifies structured data element carrying message fields
+const syslogSDID = "fields@32473"
+
+// syslogSeverities maps severities to syslog severity levels:
+// Fatal - critical (2), Error - error (3), Warning - warning (4),
+// Information - informational (6), Debug and Verbose - debug (7)
+var syslogSeverities = map[LogSeverity]int{
+	Fatal:       2,
+	Error:       3,
+	Warning:     4,
+	Information: 6,
+	Debug:       7,
+	Verbose:     7,
+}
+
+// priorityText renders syslog severity level starting text lines when PriorityPrefix is set
+func (l *Logger) priorityText(severity LogSeverity) string {
+	if !l.priority {
+		return ""
+	}
+
+	return "<" + strconv.Itoa(syslogSeverities[severity]) + ">"
+}
+
+// syslogHeader holds values of the syslog header resolved when the logger is set up
+type syslogHeader struct {
+	facility int
+	hostname string
+	appName  string
+	procID   string
+	octets   bool
+}
+
+func newSyslogHeader(item LoggerConfig, logType LogType) (*syslogHeader, error) {
+	facility := item.Facility
+	if facility == 0 {
+		facility = defaultFacility
+	}
+	if facility < 0 || facility > 23 {
+		return nil, fmt.Errorf("%d is invalid syslog facility", facility)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	appName := item.AppName
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+
+	protocol := strings.ToLower(item.Protocol)
+	octets := logType == Network && (strings.HasPrefix(protocol, "tcp") || protocol == "unix")
+
+	return &syslogHeader{
+		facility: facility,
+		hostname: syslogName(hostname, 255),
+		appName:  syslogName(appName, 48),
+		procID:   fmt.Sprint(os.Getpid()),
+		octets:   octets,
+	}, nil
+}
+
+// syslogName replaces characters not allowed in header fields and limits the length
+func syslogName(name string, max int) string {
+	name = strings.Map(func(r rune) rune {
+		if r <= 32 || r >= 127 {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if len(name) > max {
+		name = name[:max]
+	}
+	if name == "" {
+		return "-"
+	}
+
+	return name
+}
+
+// syslogParamName replaces characters not allowed in structured data parameter names, empty
+// name is replaced by _ and names already used in the element get numeric suffix, so the
+// element does not repeat them
+func syslogParamName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if r <= 32 || r >= 127 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if name == "" {
+		name = "_"
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+
+	unique := name
+	for i := 2; used[unique]; i++ {
+		suffix := "_" + strconv.Itoa(i)
+		base := name
+		if len(base)+len(suffix) > 32 {
+			base = base[:32-len(suffix)]
+		}
+		unique = base + suffix
+	}
+	used[unique] = true
+
+	return unique
+}
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// encodeSyslog renders message as RFC 5424 frame, fields are written as structured data
+func (l *Logger) encodeSyslog(t time.Time, severity LogSeverity, msg string, fields map[string]interface{}) string {
+	h := l.syslog
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%d>1 %s %s %s %s - ", h.facility*8+syslogSeverities[severity],
+		t.Format(jsonTimeLayout), h.hostname, h.appName, h.procID)
+
+	if len(fields) == 0 {
+		sb.WriteString("-")
+	} else {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		used := make(map[string]bool, len(keys))
+		sb.WriteString("[" + syslogSDID)
+		for _, k := range keys {
+			sb.WriteString(" " + syslogParamName(k, used) + `="`)
+			sb.WriteString(syslogParamEscaper.Replace(fmt.Sprintf("%v", normalizeValue(fields[k], 0))))
+			sb.WriteString(`"`)
+		}
+		sb.WriteString("]")
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(l.prefix)
+	sb.WriteString(msg)
+
+	return sb.String()
+}
+
+// writeSyslog writes syslog frame without newline when octet counting is used
+func (l *Logger) writeSyslog(w io.Writer, frame string) (int, error) {
+	if l.syslog.octets {
+		frame = fmt.Sprintf("%d %s", len(frame), frame)
+	} else {
+		frame += "\n"
+	}
+
+	return io.WriteString(w, frame)
+}
@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPriorityPrefix(t *testing.T) {
+	line := regexp.MustCompile(`^<(\d)>app: \d{4}/\d\d/\d\d \d\d:\d\d:\d\d\.\d{6} `)
+
+	for _, format := range []string{FormatText, FormatTextJSON} {
+		t.Run(format, func(t *testing.T) {
+			l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Verbose, Format: format, Prefix: "app: ", PriorityPrefix: true})
+			severities := []LogSeverity{Fatal, Error, Warning, Information, Debug, Verbose}
+			for _, s := range severities {
+				l.Log(s, "message")
+			}
+
+			lines := exportLines(l)
+			if len(lines) != len(severities) {
+				t.Fatalf("got %d lines, want %d", len(lines), len(severities))
+			}
+			for i, want := range []string{"2", "3", "4", "6", "7", "7"} {
+				m := line.FindStringSubmatch(lines[i])
+				if m == nil || m[1] != want {
+					t.Errorf("line %q does not start with <%s> priority", lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityPrefixOff(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  LoggerConfig
+	}{
+		{"disabled", LoggerConfig{LogType: "membuf", Severity: Information}},
+		{"json", LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatJSON, PriorityPrefix: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLog(t, tc.cfg)
+			l.Error("message")
+
+			if lines := exportLines(l); len(lines) != 1 || strings.HasPrefix(lines[0], "<") {
+				t.Errorf("got %q", lines)
+			}
+		})
+	}
+}
+
+func TestSyslogParamName(t *testing.T) {
+	used := map[string]bool{}
+	long := strings.Repeat("k", 40)
+	for _, tc := range []struct {
+		name, want string
+	}{
+		{"user", "user"},
+		{"", "_"},
+		{"a=b", "a_b"},
+		{"a]b", "a_b_2"},
+		{`a"b`, "a_b_3"},
+		{"_", "__2"},
+		{"sp ace", "sp_ace"},
+		{long, strings.Repeat("k", 32)},
+		{long + "x", strings.Repeat("k", 30) + "_2"},
+	} {
+		if got := syslogParamName(tc.name, used); got != tc.want {
+			t.Errorf("syslogParamName(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSyslogFrame(t *testing.T) {
+	l := newTestLog(t, LoggerConfig{LogType: "membuf", Severity: Information, Format: FormatSyslog, Facility: 16, AppName: "my app", Prefix: "svc: "})
+	l.WithFields(map[string]interface{}{"": "empty", "a=b": 1, "a]b": 2, "quote": `say "hi" \ ]`}).Error("failed")
+	l.Info("plain")
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	header := `^<%d>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) ` + regexp.QuoteMeta(syslogName(hostname, 255)) +
+		` my_app ` + strconv.Itoa(os.Getpid()) + ` - `
+
+	lines := exportLines(l)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	for i, tc := range []struct {
+		pri  int
+		rest string
+	}{
+		// local0 (16) * 8 + error (3)
+		{131, `[fields@32473 _="empty" a_b="1" a_b_2="2" quote="say \"hi\" \\ \]"] svc: failed`},
+		// local0 (16) * 8 + informational (6)
+		{134, `- svc: plain`},
+	} {
+		re := regexp.MustCompile(fmt.Sprintf(header, tc.pri) + regexp.QuoteMeta(tc.rest) + "$")
+		if !re.MatchString(lines[i]) {
+			t.Errorf("frame %s does not match %s", lines[i], re)
+		}
+	}
+}
+
+func TestSyslogOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		b, _ := ioutil.ReadAll(conn)
+		received <- b
+	}()
+
+	l := &Log{}
+	err = l.SetupLoggers(LogConfig{Loggers: []LoggerConfig{{LogType: "network", Severity: Information, Format: FormatSyslog,
+		Protocol: "tcp", Address: ln.Addr().String(), AppName: "app"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []string{"first", "second\nline"}
+	for _, msg := range msgs {
+		l.Info(msg)
+	}
+	l.Close()
+
+	var b []byte
+	select {
+	case b = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("collector received nothing")
+	}
+
+	// each frame is preceded by its length in bytes and a space, no delimiter follows it
+	data := string(b)
+	for _, msg := range msgs {
+		i := strings.IndexByte(data, ' ')
+		if i <= 0 {
+			t.Fatalf("missing frame length in %q", data)
+		}
+		n, err := strconv.Atoi(data[:i])
+		if err != nil || i+1+n > len(data) {
+			t.Fatalf("invalid frame length in %q", data)
+		}
+		frame := data[i+1 : i+1+n]
+		if !strings.HasPrefix(frame, "<14>1 ") || !strings.HasSuffix(frame, " - - "+msg) {
+			t.Errorf("got frame %q", frame)
+		}
+		data = data[i+1+n:]
+	}
+	if data != "" {
+		t.Errorf("got trailing data %q", data)
+	}
+}
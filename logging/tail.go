@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// tailBuffer keeps the most recent messages as events, so they can be rendered in any format
+type tailBuffer struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{events: make([]Event, size)}
+}
+
+func (b *tailBuffer) record(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.events[b.next] = e
+	b.next = (b.next + 1) % len(b.events)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// recent returns up to n most recent events (all of them for n <= 0) from the oldest,
+// it must be called with b.mu locked
+func (b *tailBuffer) recent(n int) []Event {
+	count := b.next
+	if b.full {
+		count = len(b.events)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	tail := make([]Event, 0, n)
+	for i := n; i > 0; i-- {
+		tail = append(tail, b.events[(b.next-i+len(b.events))%len(b.events)])
+	}
+
+	return tail
+}
+
+// drain returns up to n most recent events from the oldest and empties the buffer
+func (b *tailBuffer) drain(n int) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tail := b.recent(n)
+	for i := range b.events {
+		b.events[i] = Event{}
+	}
+	b.next, b.full = 0, false
+
+	return tail
+}
+
+// snapshot returns all kept events from the oldest
+func (b *tailBuffer) snapshot() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.recent(0)
+}
+
+// DrainTail returns up to n most recent messages kept in memory (see TailSize of LogConfig)
+// rendered as text lines and empties the buffer. It does not use locks of the logging path,
+// so it is safe to call from recover, e.g. to attach recent messages to a crash report.
+func (l *Log) DrainTail(n int) []string {
+	b := l.base()
+	if b == nil || b.tail == nil {
+		return nil
+	}
+	tail := b.tail
+
+	events := tail.drain(n)
+	lines := make([]string, 0, len(events))
+	for _, e := range events {
+		lines = append(lines, fmt.Sprintf("%s %s %s%s", e.Time.Format(textTimeLayout), getLogTypeString(e.Severity),
+			e.Message, formatFields(e.Fields)))
+	}
+
+	return lines
+}
+
+// Replay writes messages kept in memory (see TailSize of LogConfig) from the oldest into w
+// using the encoder settings, e.g. as JSON into a crash report while the log itself writes
+// text. Empty encoder renders text lines. Unlike DrainTail the messages are kept.
+// Syslog and auto formats are not supported.
+func (l *Log) Replay(w io.Writer, encoder EncoderConfig) error {
+	b := l.base()
+	if b == nil || b.tail == nil {
+		return nil
+	}
+	tail := b.tail
+
+	lg, err := newReplayLogger(encoder)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, e := range tail.snapshot() {
+		sb.WriteString(lg.render(e))
+		sb.WriteString("\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+
+	return err
+}
+
+// newReplayLogger creates logger rendering messages replayed with the encoder settings
+func newReplayLogger(encoder EncoderConfig) (*Logger, error) {
+	lg := &Logger{separator: " "}
+
+	lg.format = strings.ToLower(encoder.Format)
+	if !validFormat(lg.format) || lg.format == FormatSyslog || lg.format == FormatAuto {
+		return nil, fmt.Errorf("%s is invalid replay format", encoder.Format)
+	}
+
+	lg.severityEncoding = strings.ToLower(encoder.SeverityEncoding)
+	if !validSeverityEncoding(lg.severityEncoding) {
+		return nil, fmt.Errorf("%s is invalid severity encoding", encoder.SeverityEncoding)
+	}
+
+	lg.severityStyle = strings.ToLower(encoder.SeverityStyle)
+	if !validSeverityStyle(lg.severityStyle) {
+		return nil, fmt.Errorf("%s is invalid severity style", encoder.SeverityStyle)
+	}
+
+	var err error
+	lg.keys, err = resolveFieldKeys(encoder.FieldKeys, lg.severityEncoding, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return lg, nil
+}
+
+// render renders event as single line without newline in format of the logger
+func (l *Logger) render(e Event) string {
+	switch l.format {
+	case FormatJSON:
+		return l.encodeJSON(e.Time, e.Severity, e.Message, e.Fields)
+	case FormatTextJSON:
+		return e.Time.Format(textTimeLayout) + " " + l.severityText(e.Severity) + l.separator + e.Message +
+			l.encodeJSONFields(e.Fields)
+	}
+
+	return e.Time.Format(textTimeLayout) + " " + l.severityText(e.Severity) + l.separator + e.Message +
+		formatFields(e.Fields)
+}
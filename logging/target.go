@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// TargetFactory creates writer of custom logger target from its configuration
+type TargetFactory func(cfg LoggerConfig) (io.WriteCloser, error)
+
+var (
+	targetsMu sync.RWMutex
+	targets   = map[string]TargetFactory{}
+	// jsonTargets are custom targets decoding messages written in JSON format
+	jsonTargets = map[string]bool{}
+)
+
+var builtinTargets = []string{"file", "screen", "stderr", "network", "dual", "both", "sharded", "membuf", "console"}
+
+// RegisterTarget registers custom logger target type, loggers with LogType set to name
+// write into writer created by the factory which is closed by Close of the log
+func RegisterTarget(name string, factory func(cfg LoggerConfig) (io.WriteCloser, error)) error {
+	name = strings.ToLower(name)
+	for _, builtin := range builtinTargets {
+		if name == builtin {
+			return fmt.Errorf("%s is built-in log type", name)
+		}
+	}
+
+	if factory == nil {
+		return fmt.Errorf("factory of log type %s is not set", name)
+	}
+
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+
+	targets[name] = factory
+
+	return nil
+}
+
+// registerJSONTarget registers target decoding messages written in JSON format, format
+// of its loggers defaults to json
+func registerJSONTarget(name string, factory TargetFactory) error {
+	if err := RegisterTarget(name, factory); err != nil {
+		return err
+	}
+
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+
+	jsonTargets[strings.ToLower(name)] = true
+
+	return nil
+}
+
+// jsonTarget reports whether the custom target decodes messages written in JSON format
+func jsonTarget(name string) bool {
+	targetsMu.RLock()
+	defer targetsMu.RUnlock()
+
+	return jsonTargets[strings.ToLower(name)]
+}
+
+func targetFactory(name string) (TargetFactory, bool) {
+	targetsMu.RLock()
+	defer targetsMu.RUnlock()
+
+	factory, ok := targets[strings.ToLower(name)]
+
+	return factory, ok
+}
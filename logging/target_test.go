@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+)
+
+// recordingTarget keeps lines written into custom target
+type recordingTarget struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingTarget) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines = append(r.lines, string(p))
+
+	return len(p), nil
+}
+
+func (r *recordingTarget) Close() error {
+	return nil
+}
+
+func TestJSONTargetDefaultFormat(t *testing.T) {
+	target := &recordingTarget{}
+	err := registerJSONTarget("jsontest", func(cfg LoggerConfig) (io.WriteCloser, error) {
+		if cfg.Format != FormatJSON {
+			t.Errorf("target got format %q, want json", cfg.Format)
+		}
+		return target, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := newTestLog(t, LoggerConfig{LogType: "jsontest", Severity: Information})
+	l.Info("structured")
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if len(target.lines) != 1 {
+		t.Fatalf("got %d lines, want 1", len(target.lines))
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(target.lines[0]), &entry); err != nil || entry["msg"] != "structured" {
+		t.Errorf("line %q is not JSON entry: %v", target.lines[0], err)
+	}
+}
+
+func TestRegisterTargetBuiltin(t *testing.T) {
+	for _, name := range builtinTargets {
+		if err := RegisterTarget(name, func(cfg LoggerConfig) (io.WriteCloser, error) { return nil, nil }); err == nil {
+			t.Errorf("built-in target %s was replaced", name)
+		}
+	}
+}
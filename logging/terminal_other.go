@@ -0,0 +1,11 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package logging
+
+import "os"
+
+// isTerminal reports no file is a terminal where terminals cannot be recognized
+func isTerminal(f *os.File) bool {
+	return false
+}
@@ -0,0 +1,8 @@
+//go:build aix || linux || solaris
+// +build aix linux solaris
+
+package logging
+
+import "golang.org/x/sys/unix"
+
+const ioctlReadTermios = unix.TCGETS
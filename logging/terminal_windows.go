@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package logging
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// isTerminal reports whether the file is a console
+func isTerminal(f *os.File) bool {
+	var mode uint32
+
+	return windows.GetConsoleMode(windows.Handle(f.Fd()), &mode) == nil
+}
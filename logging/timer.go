@@ -0,0 +1,25 @@
+package logging
+
+import "time"
+
+// Timer records start time and returns function writing the elapsed duration into the log
+// with the given severity, e.g.: defer log.Timer("db.query", logging.Debug)()
+// The message is the name with fields duration (elapsed monotonic time, unaffected by clock
+// changes), start and end (wall clock timestamps) and wall_duration (difference of the wall
+// clock timestamps). Durations differing reveal the wall clock was adjusted meanwhile
+// (e.g. by NTP or after VM suspend).
+func (l *Log) Timer(name string, severity LogSeverity) func() {
+	start := time.Now()
+
+	return func() {
+		end := time.Now()
+		wallStart, wallEnd := start.Round(0), end.Round(0)
+
+		l.writeMessageKV(severity, name, []interface{}{
+			"duration", end.Sub(start).String(),
+			"wall_duration", wallEnd.Sub(wallStart).String(),
+			"start", wallStart.Format(jsonTimeLayout),
+			"end", wallEnd.Format(jsonTimeLayout),
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import "time"
+
+// TraceSeverities specifies severities of messages written by Trace
+type TraceSeverities struct {
+	// Entry is severity of the message written before the function is called
+	Entry LogSeverity
+	// Exit is severity of the message written when the function succeeds
+	Exit LogSeverity
+	// Error is severity of the message written when the function fails or panics
+	Error LogSeverity
+}
+
+var defaultTraceSeverities = TraceSeverities{Entry: Verbose, Exit: Debug, Error: Error}
+
+// SetTraceSeverities sets severities used by Trace, zero values keep the defaults
+// (Verbose on entry, Debug on exit and Error on failure)
+func (l *Log) SetTraceSeverities(s TraceSeverities) {
+	l = l.base()
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.trace = s
+}
+
+func (l *Log) traceSeverities() TraceSeverities {
+	var s TraceSeverities
+	if b := l.base(); b != nil {
+		b.mu.RLock()
+		s = b.trace
+		b.mu.RUnlock()
+	}
+
+	if s.Entry == 0 {
+		s.Entry = defaultTraceSeverities.Entry
+	}
+	if s.Exit == 0 {
+		s.Exit = defaultTraceSeverities.Exit
+	}
+	if s.Error == 0 {
+		s.Error = defaultTraceSeverities.Error
+	}
+
+	return s
+}
+
+// Trace calls fn writing messages on its entry and exit with duration and the returned error.
+// Panic of fn is written into the log and re-raised.
+func (l *Log) Trace(name string, fn func() error) (err error) {
+	s := l.traceSeverities()
+	l.writeMessage(s.Entry, name+" started")
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			l.WithFields(map[string]interface{}{"duration": time.Since(start), "panic": r}).
+				writeMessage(s.Error, name+" panicked")
+			panic(r)
+		}
+	}()
+
+	err = fn()
+	if err != nil {
+		l.WithFields(map[string]interface{}{"duration": time.Since(start), "error": err}).
+			writeMessage(s.Error, name+" failed")
+		return err
+	}
+
+	l.WithFields(map[string]interface{}{"duration": time.Since(start)}).writeMessage(s.Exit, name+" finished")
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package logging
+
+// versionKey is the field carrying the version set by SetVersion
+const versionKey = "version"
+
+// SetVersion adds version field with the given build version (e.g. release tag or commit)
+// to every message. The field is kept with default fields, so it is rendered once and not
+// per message. Empty version removes the field.
+func (l *Log) SetVersion(version string) {
+	l = l.base()
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fields := mergeFields(l.fields, nil)
+	if version == "" {
+		delete(fields, versionKey)
+	} else {
+		fields[versionKey] = version
+		l.order = appendOrder(l.order, map[string]interface{}{versionKey: version})
+	}
+	l.setFields(fields)
+}